@@ -0,0 +1,405 @@
+package sql
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func (e Executor) compileAggregate(groups []*QualifiedName, aggregates []*AggregateExpr, from PlanNode, sorted bool) (Iterator, error) {
+	in, err := e.Compile(from)
+	if err != nil {
+		return nil, err
+	}
+
+	it := aggregateIterator{from: in}
+	cols := in.Columns()
+	for _, g := range groups {
+		i, err := resolveColumn(cols, g)
+		if err != nil {
+			return nil, err
+		}
+		it.keys = append(it.keys, columnValue(i))
+		it.cols = append(it.cols, cols[i])
+	}
+	for _, a := range aggregates {
+		var arg evalFunc
+		typ := NULL
+		if a.Arg != nil {
+			var col ResultColumn
+			if arg, col, err = compileColumn(a.Arg, cols); err != nil {
+				return nil, err
+			}
+			typ = col.Type
+		}
+		it.funcs = append(it.funcs, a.Func)
+		it.args = append(it.args, arg)
+		it.cols = append(it.cols, ResultColumn{Name: a.String(), Type: aggregateType(a.Func, typ)})
+	}
+
+	if sorted {
+		return &groupAggregateIterator{aggregateIterator: it}, nil
+	}
+	return &hashAggregateIterator{aggregateIterator: it}, nil
+}
+
+// aggregateType is the type of the result of an aggregate function applied on values of the given type.
+func aggregateType(f AggregateFunc, arg DataType) DataType {
+	switch f {
+	case CountAggregate:
+		return INTEGER
+	case AvgAggregate:
+		return REAL
+	default:
+		return arg
+	}
+}
+
+// aggregateIterator holds what is common to the aggregate operators.
+// Its rows are made of the values of the keys of a group followed by the results of the aggregates.
+type aggregateIterator struct {
+	from  Iterator
+	keys  []evalFunc
+	funcs []AggregateFunc
+	// args are the arguments of the aggregates, nil for COUNT(*).
+	args []evalFunc
+	cols []ResultColumn
+}
+
+func (it *aggregateIterator) Columns() []ResultColumn { return it.cols }
+
+func (it *aggregateIterator) groupKeys(row Row) (Row, error) {
+	keys := make(Row, len(it.keys))
+	for i, fn := range it.keys {
+		v, err := fn(row)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = v
+	}
+	return keys, nil
+}
+
+func (it *aggregateIterator) newGroup(keys Row) *group {
+	g := group{keys: keys}
+	for _, f := range it.funcs {
+		g.aggs = append(g.aggs, newAggregator(f))
+	}
+	return &g
+}
+
+// add accumulates a row in the aggregates of a group.
+func (it *aggregateIterator) add(g *group, row Row) error {
+	for i, agg := range g.aggs {
+		var v Value = true
+		if it.args[i] != nil {
+			var err error
+			if v, err = it.args[i](row); err != nil {
+				return err
+			}
+		}
+		if err := agg.add(v); err != nil {
+			return fmt.Errorf("%s: %w", it.cols[len(it.keys)+i].Name, err)
+		}
+	}
+	return nil
+}
+
+// group is the state of the aggregates of the rows sharing the same keys.
+type group struct {
+	keys Row
+	aggs []aggregator
+}
+
+func (g *group) row() Row {
+	row := make(Row, 0, len(g.keys)+len(g.aggs))
+	row = append(row, g.keys...)
+	for _, agg := range g.aggs {
+		row = append(row, agg.result())
+	}
+	return row
+}
+
+// hashAggregateIterator consumes its input on Open, gathering the groups in a hash table.
+// The groups are returned in the order they are first seen.
+type hashAggregateIterator struct {
+	aggregateIterator
+	groups []*group
+	pos    int
+}
+
+func (it *hashAggregateIterator) Open() error {
+	if err := it.from.Open(); err != nil {
+		return err
+	}
+
+	it.groups = nil
+	it.pos = 0
+	index := make(map[string]*group)
+	for {
+		row, err := it.from.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		keys, err := it.groupKeys(row)
+		if err != nil {
+			return err
+		}
+		k := groupKey(keys)
+		g, ok := index[k]
+		if !ok {
+			g = it.newGroup(keys)
+			index[k] = g
+			it.groups = append(it.groups, g)
+		}
+		if err := it.add(g, row); err != nil {
+			return err
+		}
+	}
+
+	if len(it.groups) == 0 && len(it.keys) == 0 {
+		it.groups = append(it.groups, it.newGroup(nil))
+	}
+	return nil
+}
+
+func (it *hashAggregateIterator) Next() (Row, error) {
+	if it.pos >= len(it.groups) {
+		return nil, io.EOF
+	}
+	g := it.groups[it.pos]
+	it.pos++
+	return g.row(), nil
+}
+
+func (it *hashAggregateIterator) Close() error {
+	it.groups = nil
+	return it.from.Close()
+}
+
+// groupKey encodes the keys of a group so that equal keys share the same encoding.
+// Unlike joins, NULL keys are all part of the same group.
+func groupKey(keys Row) string {
+	var sb strings.Builder
+	for _, v := range keys {
+		k, ok := hashKey(v)
+		if !ok {
+			sb.WriteString("NULL;")
+			continue
+		}
+		fmt.Fprintf(&sb, "%T:%q;", k, fmt.Sprint(k))
+	}
+	return sb.String()
+}
+
+// groupAggregateIterator aggregates an input sorted on the keys, a group is returned as soon
+// as a row with different keys is found.
+type groupAggregateIterator struct {
+	aggregateIterator
+	cur  *group
+	done bool
+}
+
+func (it *groupAggregateIterator) Open() error {
+	it.cur = nil
+	it.done = false
+	return it.from.Open()
+}
+
+func (it *groupAggregateIterator) Next() (Row, error) {
+	if it.done {
+		return nil, io.EOF
+	}
+	for {
+		row, err := it.from.Next()
+		if err == io.EOF {
+			it.done = true
+			if it.cur == nil {
+				if len(it.keys) > 0 {
+					return nil, io.EOF
+				}
+				// Without groups, an empty input is still aggregated in a single row.
+				it.cur = it.newGroup(nil)
+			}
+			return it.cur.row(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		keys, err := it.groupKeys(row)
+		if err != nil {
+			return nil, err
+		}
+
+		var out Row
+		if it.cur != nil {
+			same, err := sameKeys(it.cur.keys, keys)
+			if err != nil {
+				return nil, err
+			}
+			if !same {
+				out = it.cur.row()
+				it.cur = nil
+			}
+		}
+		if it.cur == nil {
+			it.cur = it.newGroup(keys)
+		}
+		if err := it.add(it.cur, row); err != nil {
+			return nil, err
+		}
+		if out != nil {
+			return out, nil
+		}
+	}
+}
+
+// sameKeys checks if two groups keys are equal, NULL keys being equal.
+func sameKeys(a, b Row) (bool, error) {
+	for i := range a {
+		c, err := compareValues(a[i], b[i])
+		if err != nil || c != 0 {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (it *groupAggregateIterator) Close() error {
+	it.cur = nil
+	return it.from.Close()
+}
+
+// aggregator accumulates the values of a group.
+type aggregator interface {
+	add(v Value) error
+	result() Value
+}
+
+func newAggregator(f AggregateFunc) aggregator {
+	switch f {
+	case CountAggregate:
+		return &countAggregator{}
+	case SumAggregate:
+		return &sumAggregator{}
+	case AvgAggregate:
+		return &avgAggregator{}
+	case MinAggregate:
+		return &extremumAggregator{sign: -1}
+	default:
+		return &extremumAggregator{sign: 1}
+	}
+}
+
+// countAggregator counts the values which are not NULL.
+type countAggregator struct {
+	n int64
+}
+
+func (a *countAggregator) add(v Value) error {
+	if v != nil {
+		a.n++
+	}
+	return nil
+}
+
+func (a *countAggregator) result() Value { return a.n }
+
+// sumAggregator adds the values which are not NULL, the sum of INTEGER values is an INTEGER.
+// The sum of no values is NULL.
+type sumAggregator struct {
+	sum Value
+}
+
+func (a *sumAggregator) add(v Value) error {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case int64:
+		switch s := a.sum.(type) {
+		case nil:
+			a.sum = x
+		case int64:
+			sum, err := intArithmetic(PLUS, s, x)
+			if err != nil {
+				return err
+			}
+			a.sum = sum
+		case float64:
+			a.sum = s + float64(x)
+		}
+	case float64:
+		switch s := a.sum.(type) {
+		case nil:
+			a.sum = x
+		case int64:
+			a.sum = float64(s) + x
+		case float64:
+			a.sum = s + x
+		}
+	default:
+		return fmt.Errorf("cannot sum %v", v)
+	}
+	return nil
+}
+
+func (a *sumAggregator) result() Value { return a.sum }
+
+// avgAggregator computes the mean of the values which are not NULL.
+type avgAggregator struct {
+	sum float64
+	n   int64
+}
+
+func (a *avgAggregator) add(v Value) error {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case int64:
+		a.sum += float64(x)
+	case float64:
+		a.sum += x
+	default:
+		return fmt.Errorf("cannot average %v", v)
+	}
+	a.n++
+	return nil
+}
+
+func (a *avgAggregator) result() Value {
+	if a.n == 0 {
+		return nil
+	}
+	return a.sum / float64(a.n)
+}
+
+// extremumAggregator keeps the lowest (sign -1) or greatest (sign 1) value which is not NULL.
+type extremumAggregator struct {
+	sign int
+	v    Value
+}
+
+func (a *extremumAggregator) add(v Value) error {
+	if v == nil {
+		return nil
+	}
+	if a.v == nil {
+		a.v = v
+		return nil
+	}
+	c, err := compareValues(v, a.v)
+	if err != nil {
+		return err
+	}
+	if c*a.sign > 0 {
+		a.v = v
+	}
+	return nil
+}
+
+func (a *extremumAggregator) result() Value { return a.v }
@@ -0,0 +1,39 @@
+package sql
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_sumAggregator(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []Value
+		want    Value
+		wantErr bool
+	}{
+		{name: "no rows", want: nil},
+		{name: "integers", values: []Value{int64(1), nil, int64(2)}, want: int64(3)},
+		{name: "promotion", values: []Value{int64(1), 0.5}, want: 1.5},
+		{name: "integer overflow", values: []Value{int64(math.MaxInt64), int64(1)}, wantErr: true},
+		{name: "integer underflow", values: []Value{int64(math.MinInt64), int64(-1)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a sumAggregator
+			var err error
+			for _, v := range tt.values {
+				if err = a.add(v); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(a.result(), tt.want) {
+				t.Errorf("result() = %v, want %v", a.result(), tt.want)
+			}
+		})
+	}
+}
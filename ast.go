@@ -1,5 +1,11 @@
 package sql
 
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
 type JoinKind int
 
 const (
@@ -9,91 +15,476 @@ const (
 	FullOuterJoin
 )
 
+// AggregateFunc is a function computing a single value from the rows of a group.
+type AggregateFunc int
+
+const (
+	CountAggregate AggregateFunc = iota
+	SumAggregate
+	AvgAggregate
+	MinAggregate
+	MaxAggregate
+)
+
+var aggregateFuncs = map[AggregateFunc]string{
+	CountAggregate: "COUNT",
+	SumAggregate:   "SUM",
+	AvgAggregate:   "AVG",
+	MinAggregate:   "MIN",
+	MaxAggregate:   "MAX",
+}
+
+func (f AggregateFunc) String() string {
+	if str, ok := aggregateFuncs[f]; ok {
+		return str
+	}
+	return "N/A"
+}
+
+// lookupAggregateFunc finds an aggregate function by its case insensitive name.
+func lookupAggregateFunc(name string) (AggregateFunc, bool) {
+	name = strings.ToUpper(name)
+	for f, str := range aggregateFuncs {
+		if str == name {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
 type Stmt interface {
 	stmtNode()
 }
 
 type SelectStmt struct {
-	From    FromClause
-	Fields  []Ident
-	Where   *WhereClause
-	GroupBy *GroupByClause
-	OrderBy *OrderByClause
-	Limit   *LimitClause
-	Offset  *OffsetClause
+	Distinct bool
+	From     FromClause
+	Fields   []Expr
+	Where    *WhereClause
+	GroupBy  *GroupByClause
+	Having   *HavingClause
+	OrderBy  *OrderByClause
+	Limit    *LimitClause
+	Offset   *OffsetClause
+	Pos      Pos
 }
 
 func (*SelectStmt) stmtNode() {}
 
+// Expr is an expression. The nodes of the syntax tree hold their position in the source of the statement,
+// the position of an expression is the one of its first character.
 type Expr interface {
 	exprNode()
+	Position() Pos
 }
 
+// Ident is a plain identifier, such as an alias.
 type Ident struct {
 	Name string
+	Pos  Pos
+}
+
+// QualifiedName refers to a column, or to a relation of the FROM clause, by its name optionally qualified
+// by the names of the objects holding it, as in catalog.schema.table.column. The qualifiers which are not
+// written are empty, and so is the Column of a relation.
+// A Column named * stands for all the columns of the relations, or of the relation named by Table.
+type QualifiedName struct {
+	Catalog string
+	Schema  string
+	Table   string
+	Column  string
+	Pos     Pos
 }
 
 type BasicLit struct {
 	Kind  Token
 	Value string
+	Pos   Pos
 }
 
 type UnaryExpr struct {
-	Op Token
-	X  Expr
+	Op  Token
+	X   Expr
+	Pos Pos
 }
 
 type BinaryExpr struct {
 	LHS Expr
 	Op  Token
 	RHS Expr
+	Pos Pos
+}
+
+// IsNullExpr tests whether X is NULL, or is not NULL when Not is set.
+type IsNullExpr struct {
+	X   Expr
+	Not bool
+	Pos Pos
+}
+
+// InExpr tests whether X is equal to one of the values of List, or to none of them when Not is set.
+type InExpr struct {
+	X    Expr
+	List []Expr
+	Not  bool
+	Pos  Pos
+}
+
+// BetweenExpr tests whether X is within the inclusive range from Low to High, or outside of it when Not is set.
+type BetweenExpr struct {
+	X    Expr
+	Low  Expr
+	High Expr
+	Not  bool
+	Pos  Pos
+}
+
+// LikeExpr matches X against Pattern, where % stands for any sequence of characters and _ for any character.
+// The optional Escape character makes the character following it in the pattern match itself.
+type LikeExpr struct {
+	X       Expr
+	Pattern Expr
+	Escape  Expr
+	Not     bool
+	Pos     Pos
+}
+
+// CaseExpr is a conditional expression. When Operand is set, it is compared to the condition of each
+// WHEN clause (simple CASE), otherwise the conditions are predicates (searched CASE).
+// The result of the first matching clause is returned, Else being the result when no clause matches.
+// A nil Else stands for ELSE NULL.
+type CaseExpr struct {
+	Operand Expr
+	Whens   []*WhenClause
+	Else    Expr
+	Pos     Pos
+}
+
+type WhenClause struct {
+	Cond   Expr
+	Result Expr
+	Pos    Pos
 }
 
 type AliasExpr struct {
 	Expr  Expr
 	Alias Ident
+	Pos   Pos
 }
 
-func (*Ident) exprNode()      {}
-func (*BasicLit) exprNode()   {}
-func (*UnaryExpr) exprNode()  {}
-func (*BinaryExpr) exprNode() {}
-func (*AliasExpr) exprNode()  {}
+// CastExpr converts the value of X to Type, written CAST(x AS type) or x::type.
+type CastExpr struct {
+	X    Expr
+	Type DataType
+	Pos  Pos
+}
+
+// CallExpr is a call to a scalar function, the function is looked up by its case insensitive name
+// among the registered functions.
+type CallExpr struct {
+	Name string
+	Args []Expr
+	Pos  Pos
+}
+
+// AggregateExpr is a call to an aggregate function, a nil Arg stands for COUNT(*).
+type AggregateExpr struct {
+	Func AggregateFunc
+	Arg  Expr
+	Pos  Pos
+}
+
+func (*QualifiedName) exprNode() {}
+func (*BasicLit) exprNode()      {}
+func (*UnaryExpr) exprNode()     {}
+func (*BinaryExpr) exprNode()    {}
+func (*IsNullExpr) exprNode()    {}
+func (*InExpr) exprNode()        {}
+func (*BetweenExpr) exprNode()   {}
+func (*LikeExpr) exprNode()      {}
+func (*CaseExpr) exprNode()      {}
+func (*CastExpr) exprNode()      {}
+func (*CallExpr) exprNode()      {}
+func (*AliasExpr) exprNode()     {}
+func (*AggregateExpr) exprNode() {}
+
+func (e *QualifiedName) Position() Pos { return e.Pos }
+func (e *BasicLit) Position() Pos      { return e.Pos }
+func (e *UnaryExpr) Position() Pos     { return e.Pos }
+func (e *BinaryExpr) Position() Pos    { return e.Pos }
+func (e *IsNullExpr) Position() Pos    { return e.Pos }
+func (e *InExpr) Position() Pos        { return e.Pos }
+func (e *BetweenExpr) Position() Pos   { return e.Pos }
+func (e *LikeExpr) Position() Pos      { return e.Pos }
+func (e *CaseExpr) Position() Pos      { return e.Pos }
+func (e *CastExpr) Position() Pos      { return e.Pos }
+func (e *CallExpr) Position() Pos      { return e.Pos }
+func (e *AliasExpr) Position() Pos     { return e.Pos }
+func (e *AggregateExpr) Position() Pos { return e.Pos }
+
+// Inspect traverses an expression in depth-first order, calling f for each node.
+// The children of a node are not visited when f returns false.
+func Inspect(expr Expr, f func(Expr) bool) {
+	if expr == nil || !f(expr) {
+		return
+	}
+
+	switch e := expr.(type) {
+	case *UnaryExpr:
+		Inspect(e.X, f)
+	case *BinaryExpr:
+		Inspect(e.LHS, f)
+		Inspect(e.RHS, f)
+	case *IsNullExpr:
+		Inspect(e.X, f)
+	case *InExpr:
+		Inspect(e.X, f)
+		for _, v := range e.List {
+			Inspect(v, f)
+		}
+	case *BetweenExpr:
+		Inspect(e.X, f)
+		Inspect(e.Low, f)
+		Inspect(e.High, f)
+	case *LikeExpr:
+		Inspect(e.X, f)
+		Inspect(e.Pattern, f)
+		Inspect(e.Escape, f)
+	case *CaseExpr:
+		Inspect(e.Operand, f)
+		for _, w := range e.Whens {
+			Inspect(w.Cond, f)
+			Inspect(w.Result, f)
+		}
+		Inspect(e.Else, f)
+	case *CastExpr:
+		Inspect(e.X, f)
+	case *CallExpr:
+		for _, arg := range e.Args {
+			Inspect(arg, f)
+		}
+	case *AliasExpr:
+		Inspect(e.Expr, f)
+	case *AggregateExpr:
+		Inspect(e.Arg, f)
+	}
+}
+
+// hasAggregate checks if an expression calls an aggregate function.
+func hasAggregate(expr Expr) bool {
+	found := false
+	Inspect(expr, func(e Expr) bool {
+		if _, ok := e.(*AggregateExpr); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
 
 type WhereClause struct {
 	Predicate Expr
+	Pos       Pos
+}
+
+type HavingClause struct {
+	Predicate Expr
+	Pos       Pos
 }
 
 type OrderByClause struct {
-	Fields []*Ident
+	Fields []Expr
+	Pos    Pos
 }
 
 type GroupByClause struct {
-	Fields []*Ident
+	Fields []*QualifiedName
+	Pos    Pos
 }
 
 type FromClause struct {
 	TableName Expr
 	Join      *JoinSubClause
+	Pos       Pos
 }
 
 type JoinSubClause struct {
 	TableName Expr
 	Kind      JoinKind
-	Criterion BinaryExpr
+	Criterion Expr
 	Join      *JoinSubClause
+	Pos       Pos
+}
+
+type LimitClause struct {
+	Value int
+	Pos   Pos
 }
 
-type LimitClause struct{ Value int }
-type OffsetClause struct{ Value int }
+type OffsetClause struct {
+	Value int
+	Pos   Pos
+}
 
 func (id *Ident) String() string {
 	if id == nil {
 		return "NULL"
 	}
-	return id.Name
+	return quoteIdent(id.Name)
+}
+
+func (n *QualifiedName) String() string {
+	if n == nil {
+		return "NULL"
+	}
+	var parts []string
+	for _, part := range []string{n.Catalog, n.Schema, n.Table, n.Column} {
+		if part != "" {
+			parts = append(parts, quoteIdent(part))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteIdent writes an identifier, enclosed in double quotes when it would not be read back as the same name.
+func quoteIdent(name string) string {
+	if name == "*" || (isPlainIdent(name) && tokenizeLiteral(name) == IDENT) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// isPlainIdent checks if a name is scanned as an identifier without quotes.
+func isPlainIdent(name string) bool {
+	for i, ch := range name {
+		if !unicode.IsLetter(ch) && (i == 0 || (!unicode.IsDigit(ch) && ch != '_')) {
+			return false
+		}
+	}
+	return name != ""
 }
 
 func (l *BasicLit) String() string {
+	switch l.Kind {
+	case STRING:
+		return quoteString(l.Value)
+	case DATE, TIMESTAMP:
+		return l.Kind.String() + " " + quoteString(l.Value)
+	}
 	return l.Value
 }
+
+// quoteString writes a string literal, doubling its quotes.
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func (e *UnaryExpr) String() string {
+	if e.Op.IsKeyword() {
+		return fmt.Sprintf("%s %s", e.Op.Symbol(), operandString(e.X, e.Op.UnaryPrecedence()))
+	}
+	return e.Op.Symbol() + operandString(e.X, e.Op.UnaryPrecedence())
+}
+
+// String writes the expression back with the parentheses needed to keep its meaning.
+func (e *BinaryExpr) String() string {
+	prec := e.Op.Precedence()
+	return fmt.Sprintf("%s %s %s", operandString(e.LHS, prec), e.Op.Symbol(), operandString(e.RHS, prec+1))
+}
+
+func (e *IsNullExpr) String() string {
+	if e.Not {
+		return operandString(e.X, IS.Precedence()+1) + " IS NOT NULL"
+	}
+	return operandString(e.X, IS.Precedence()+1) + " IS NULL"
+}
+
+func (e *InExpr) String() string {
+	list := make([]string, len(e.List))
+	for i, v := range e.List {
+		list[i] = fmt.Sprint(v)
+	}
+	return fmt.Sprintf("%s %sIN (%s)", operandString(e.X, IN.Precedence()+1), notString(e.Not), strings.Join(list, ", "))
+}
+
+func (e *BetweenExpr) String() string {
+	prec := BETWEEN.Precedence() + 1
+	return fmt.Sprintf("%s %sBETWEEN %s AND %s",
+		operandString(e.X, prec), notString(e.Not), operandString(e.Low, prec), operandString(e.High, prec))
+}
+
+func (e *LikeExpr) String() string {
+	prec := LIKE.Precedence() + 1
+	s := fmt.Sprintf("%s %sLIKE %s", operandString(e.X, prec), notString(e.Not), operandString(e.Pattern, prec))
+	if e.Escape != nil {
+		s += " ESCAPE " + operandString(e.Escape, prec)
+	}
+	return s
+}
+
+func (e *CaseExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString("CASE")
+	if e.Operand != nil {
+		fmt.Fprintf(&sb, " %s", e.Operand)
+	}
+	for _, w := range e.Whens {
+		fmt.Fprintf(&sb, " WHEN %s THEN %s", w.Cond, w.Result)
+	}
+	if e.Else != nil {
+		fmt.Fprintf(&sb, " ELSE %s", e.Else)
+	}
+	sb.WriteString(" END")
+	return sb.String()
+}
+
+func notString(not bool) string {
+	if not {
+		return "NOT "
+	}
+	return ""
+}
+
+// operandString writes an operand, enclosed in parentheses when its operator binds looser than prec.
+func operandString(expr Expr, prec int) string {
+	var opPrec int
+	switch e := expr.(type) {
+	case *BinaryExpr:
+		opPrec = e.Op.Precedence()
+	case *UnaryExpr:
+		opPrec = e.Op.UnaryPrecedence()
+	case *IsNullExpr:
+		opPrec = IS.Precedence()
+	case *InExpr, *BetweenExpr, *LikeExpr:
+		opPrec = IN.Precedence()
+	default:
+		return fmt.Sprint(expr)
+	}
+	if opPrec < prec {
+		return fmt.Sprintf("(%s)", expr)
+	}
+	return fmt.Sprint(expr)
+}
+
+func (a *AliasExpr) String() string {
+	return fmt.Sprintf("%s AS %s", a.Expr, quoteIdent(a.Alias.Name))
+}
+
+func (e *CastExpr) String() string {
+	return fmt.Sprintf("CAST(%s AS %s)", e.X, e.Type)
+}
+
+func (e *CallExpr) String() string {
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = fmt.Sprint(arg)
+	}
+	return fmt.Sprintf("%s(%s)", e.Name, strings.Join(args, ", "))
+}
+
+func (a *AggregateExpr) String() string {
+	if a.Arg == nil {
+		return a.Func.String() + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", a.Func, a.Arg)
+}
@@ -0,0 +1,187 @@
+package sql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// casts lists the types each type can be converted to by CAST, besides itself:
+//
+//	from \ to  TEXT  INTEGER  REAL  BOOLEAN  DATETIME  BLOB
+//	TEXT       -     parse    parse parse    parse     bytes
+//	INTEGER    yes   -        yes   yes      no        no
+//	REAL       yes   round    -     no       no        no
+//	BOOLEAN    yes   1 or 0   no    -        no        no
+//	DATETIME   yes   no       no    no       -         no
+//	BLOB       bytes no       no    no       no        -
+//
+// TEXT is parsed with the syntax of the literals of the target type, leading and trailing spaces ignored:
+// BOOLEAN accepts true, false, t, f, 1 and 0 in any case and DATETIME an ISO-8601 date or timestamp.
+// A REAL is rounded half away from zero to an INTEGER, a non zero INTEGER is a true BOOLEAN.
+// DATETIME values are written in ISO-8601 and BLOB values are converted byte for byte.
+//
+// Values are implicitly converted when values of different types meet in an operation, such as
+// a comparison or the branches of a CASE, along a subset of these conversions: INTEGER to REAL
+// and TEXT to DATETIME.
+var casts = map[DataType][]DataType{
+	TEXT:     {INTEGER, REAL, BOOLEAN, DATETIME, BLOB},
+	INTEGER:  {TEXT, REAL, BOOLEAN},
+	REAL:     {TEXT, INTEGER},
+	BOOLEAN:  {TEXT, INTEGER},
+	DATETIME: {TEXT},
+	BLOB:     {TEXT},
+}
+
+// canCast checks if values of a type can be converted to another, NULL can be converted to any type.
+func canCast(from, to DataType) bool {
+	if from == NULL || from == to {
+		return true
+	}
+	for _, t := range casts[from] {
+		if t == to {
+			return true
+		}
+	}
+	return false
+}
+
+// implicitCast checks if values of a type are converted to another when they meet values of that type.
+func implicitCast(from, to DataType) bool {
+	return from == NULL || from == to || (from == INTEGER && to == REAL) || (from == TEXT && to == DATETIME)
+}
+
+// comparisonType is the type the operands of a comparison are converted to before being compared.
+func comparisonType(l, r DataType) (DataType, error) {
+	t, err := commonType(l, r)
+	if err != nil {
+		return NULL, fmt.Errorf("cannot compare %s and %s", l, r)
+	}
+	return t, nil
+}
+
+// implicitCoerce converts the values computed by fn from a type to another, if needed.
+// Numeric values are left as they are, INTEGER and REAL values being operated on together.
+func implicitCoerce(fn evalFunc, from, to DataType) evalFunc {
+	if !needsCoercion(from, to) {
+		return fn
+	}
+	return coerce(fn, to)
+}
+
+// needsCoercion checks if the values of a type are converted to be operated on as another type.
+func needsCoercion(from, to DataType) bool {
+	return from != NULL && from != to && numericType(from, to) == NULL
+}
+
+func compileCastExpr(e *CastExpr, cols []ResultColumn) (evalFunc, error) {
+	if from := exprType(e.X, cols); !canCast(from, e.Type) {
+		return nil, fmt.Errorf("cannot cast %s to %s", from, e.Type)
+	}
+	x, err := compileExpr(e.X, cols)
+	if err != nil {
+		return nil, err
+	}
+	return coerce(x, e.Type), nil
+}
+
+// coerce converts the values computed by fn to a type.
+func coerce(fn evalFunc, to DataType) evalFunc {
+	return func(row Row) (Value, error) {
+		v, err := fn(row)
+		if err != nil {
+			return nil, err
+		}
+		return castValue(v, to)
+	}
+}
+
+// castValue converts a value to a type, NULL stays NULL.
+func castValue(v Value, to DataType) (Value, error) {
+	from := valueType(v)
+	if from == NULL || from == to {
+		return v, nil
+	}
+	if !canCast(from, to) {
+		return nil, fmt.Errorf("cannot cast %s to %s", from, to)
+	}
+
+	switch x := v.(type) {
+	case string:
+		return parseValue(x, to)
+	case int64:
+		switch to {
+		case TEXT:
+			return strconv.FormatInt(x, 10), nil
+		case REAL:
+			return float64(x), nil
+		case BOOLEAN:
+			return x != 0, nil
+		}
+	case float64:
+		switch to {
+		case TEXT:
+			return strconv.FormatFloat(x, 'g', -1, 64), nil
+		case INTEGER:
+			r := math.Round(x)
+			if math.IsNaN(r) || r < math.MinInt64 || r >= math.MaxInt64 {
+				return nil, fmt.Errorf("INTEGER out of range: %v", x)
+			}
+			return int64(r), nil
+		}
+	case bool:
+		switch to {
+		case TEXT:
+			return strconv.FormatBool(x), nil
+		case INTEGER:
+			if x {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		}
+	case time.Time:
+		return x.Format(time.RFC3339Nano), nil
+	case []byte:
+		return string(x), nil
+	}
+	return nil, fmt.Errorf("cannot cast %s to %s", from, to)
+}
+
+// parseValue converts a TEXT value to another type.
+func parseValue(s string, to DataType) (Value, error) {
+	if to == BLOB {
+		return []byte(s), nil
+	}
+
+	s = strings.TrimSpace(s)
+	switch to {
+	case INTEGER:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid INTEGER \"%s\"", s)
+		}
+		return v, nil
+	case REAL:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REAL \"%s\"", s)
+		}
+		return v, nil
+	case BOOLEAN:
+		v, err := strconv.ParseBool(strings.ToLower(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid BOOLEAN \"%s\"", s)
+		}
+		return v, nil
+	case DATETIME:
+		v, err := parseDatetime(s)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("cannot cast TEXT to %s", to)
+	}
+}
@@ -0,0 +1,208 @@
+package sql
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// CSVStorage reads relations from CSV files.
+//
+// The Location of a relation is the path of its file, relative to the storage directory,
+// it defaults to "<name>.csv". The first line of a file is a header naming its columns.
+// The Location of a column is either its index (int) or its name (string) in the header,
+// it defaults to the name of the column. The columns of the file missing from the relation are skipped.
+type CSVStorage struct {
+	dir string
+}
+
+func NewCSVStorage(dir string) *CSVStorage {
+	return &CSVStorage{dir: dir}
+}
+
+// Scan returns an iterator over the rows of the file of the relation.
+// The columns are returned in the order of the relation, whatever their order in the file.
+func (s *CSVStorage) Scan(r Relation) (Iterator, error) {
+	path, err := s.path(r)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := readCSVHeader(path)
+	if err != nil {
+		return nil, err
+	}
+
+	byIndex := make(map[int]Column)
+	it := csvIterator{path: path}
+	for _, col := range r.Columns {
+		i, err := csvColumnIndex(header, col)
+		if err != nil {
+			return nil, fmt.Errorf("relation %s: %w", r.Name, err)
+		}
+		if prev, ok := byIndex[i]; ok {
+			return nil, fmt.Errorf("relation %s: columns %s and %s share the same location", r.Name, prev.Name, col.Name)
+		}
+		byIndex[i] = col
+		it.idx = append(it.idx, i)
+		it.cols = append(it.cols, ResultColumn{Table: r.Name, Name: col.Name, Type: col.Type})
+	}
+	return &it, nil
+}
+
+func (s *CSVStorage) path(r Relation) (string, error) {
+	var path string
+	switch loc := r.Location.(type) {
+	case nil:
+		path = r.Name + ".csv"
+	case string:
+		path = loc
+	default:
+		return "", fmt.Errorf("relation %s: invalid location %v", r.Name, r.Location)
+	}
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	return filepath.Join(s.dir, path), nil
+}
+
+func readCSVHeader(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	header, err := csv.NewReader(f).Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%s: missing header", path)
+	}
+	return header, err
+}
+
+func csvColumnIndex(header []string, col Column) (int, error) {
+	name := col.Name
+	switch loc := col.Location.(type) {
+	case nil:
+	case int:
+		if loc < 0 || loc >= len(header) {
+			return -1, fmt.Errorf("column %s: index %d out of range", col.Name, loc)
+		}
+		return loc, nil
+	case string:
+		name = loc
+	default:
+		return -1, fmt.Errorf("column %s: invalid location %v", col.Name, col.Location)
+	}
+
+	for i, h := range header {
+		if h == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("column %s: not found in header", col.Name)
+}
+
+// csvIterator streams the rows of a CSV file, decoding each field with the type of its column.
+type csvIterator struct {
+	path string
+	idx  []int
+	cols []ResultColumn
+	f    *os.File
+	r    *csv.Reader
+	line int
+}
+
+func (it *csvIterator) Columns() []ResultColumn { return it.cols }
+
+func (it *csvIterator) Open() error {
+	if it.f != nil {
+		return errors.New("iterator already opened")
+	}
+	f, err := os.Open(it.path)
+	if err != nil {
+		return err
+	}
+	it.f = f
+	it.r = csv.NewReader(f)
+	it.r.ReuseRecord = true
+
+	// skip the header
+	if _, err := it.r.Read(); err != nil && err != io.EOF {
+		return err
+	}
+	it.line = 1
+	return nil
+}
+
+func (it *csvIterator) Next() (Row, error) {
+	if it.r == nil {
+		return nil, errors.New("iterator not opened")
+	}
+	record, err := it.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	it.line++
+
+	row := make(Row, len(it.idx))
+	for i, j := range it.idx {
+		v, err := decodeValue(record[j], it.cols[i].Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: column %s: %w", it.path, it.line, it.cols[i].Name, err)
+		}
+		row[i] = v
+	}
+	return row, nil
+}
+
+func (it *csvIterator) Close() error {
+	if it.f == nil {
+		return nil
+	}
+	err := it.f.Close()
+	it.f = nil
+	it.r = nil
+	return err
+}
+
+// decodeValue converts a stored field into a value of the given type, empty fields are NULL.
+// DATETIME fields are ISO-8601 dates or timestamps.
+func decodeValue(s string, t DataType) (Value, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	switch t {
+	case TEXT:
+		return s, nil
+	case DATETIME:
+		return parseDatetime(s)
+	case INTEGER:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid INTEGER \"%s\"", s)
+		}
+		return v, nil
+	case REAL:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REAL \"%s\"", s)
+		}
+		return v, nil
+	case BOOLEAN:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BOOLEAN \"%s\"", s)
+		}
+		return v, nil
+	case BLOB:
+		return []byte(s), nil
+	default:
+		return nil, fmt.Errorf("cannot decode values of type %s", t)
+	}
+}
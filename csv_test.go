@@ -0,0 +1,230 @@
+package sql_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	sql "github.com/ndilsou/go-rdbms-playground"
+)
+
+func TestCSVStorage_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		relation sql.Relation
+		cols     []string
+		want     []sql.Row
+		wantErr  bool
+	}{
+		{
+			name:    "typed columns",
+			content: "id,name,score,active\n1,alice,1.5,true\n2,bob,,false\n",
+			relation: sql.Relation{
+				Name: "people",
+				Columns: []sql.Column{
+					{Name: "id", Type: sql.INTEGER},
+					{Name: "name", Type: sql.TEXT},
+					{Name: "score", Type: sql.REAL},
+					{Name: "active", Type: sql.BOOLEAN},
+				},
+			},
+			cols: []string{"people.id", "people.name", "people.score", "people.active"},
+			want: []sql.Row{
+				{int64(1), "alice", 1.5, true},
+				{int64(2), "bob", nil, false},
+			},
+		},
+		{
+			name:    "column locations",
+			content: "id,full name,ignored\n1,alice,x\n2,bob,y\n",
+			relation: sql.Relation{
+				Name:     "people",
+				Location: "people.csv",
+				Columns: []sql.Column{
+					{Name: "name", Type: sql.TEXT, Location: "full name"},
+					{Name: "id", Type: sql.INTEGER, Location: 0},
+				},
+			},
+			cols: []string{"people.name", "people.id"},
+			want: []sql.Row{
+				{"alice", int64(1)},
+				{"bob", int64(2)},
+			},
+		},
+		{
+			name:    "datetime column",
+			content: "id,at\n1,2024-01-02\n2,2024-01-02T03:04:05Z\n3,2024-01-02 03:04:05.5+02:00\n4,\n",
+			relation: sql.Relation{
+				Name: "people",
+				Columns: []sql.Column{
+					{Name: "id", Type: sql.INTEGER},
+					{Name: "at", Type: sql.DATETIME},
+				},
+			},
+			cols: []string{"people.id", "people.at"},
+			want: []sql.Row{
+				{int64(1), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+				{int64(2), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+				{int64(3), time.Date(2024, 1, 2, 1, 4, 5, 500000000, time.UTC)},
+				{int64(4), nil},
+			},
+		},
+		{
+			name:    "invalid datetime",
+			content: "at\n2024-13-01\n",
+			relation: sql.Relation{
+				Name:    "people",
+				Columns: []sql.Column{{Name: "at", Type: sql.DATETIME}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "header only",
+			content: "id\n",
+			relation: sql.Relation{
+				Name:    "people",
+				Columns: []sql.Column{{Name: "id", Type: sql.INTEGER}},
+			},
+			cols: []string{"people.id"},
+		},
+		{
+			name:    "unknown column",
+			content: "id\n1\n",
+			relation: sql.Relation{
+				Name:    "people",
+				Columns: []sql.Column{{Name: "name", Type: sql.TEXT}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			content: "id\nabc\n",
+			relation: sql.Relation{
+				Name:    "people",
+				Columns: []sql.Column{{Name: "id", Type: sql.INTEGER}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "people.csv"), []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cols, rows, err := scanAll(sql.NewCSVStorage(dir), tt.relation)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(cols, tt.cols) {
+				t.Errorf("Scan() columns = %v, want %v", cols, tt.cols)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("Scan() rows = %v, want %v", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVStorage_Query(t *testing.T) {
+	dir := t.TempDir()
+	content := "c,b,a\nx,1.5,1\ny,,2\nx,2.5,3\n"
+	if err := os.WriteFile(filepath.Join(dir, "t1.csv"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := sql.NewParser(strings.NewReader(`SELECT a, b FROM t1 WHERE c = 'x'`)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := sql.NewExecutor(&mockCatalog{}, sql.NewCSVStorage(dir))
+	_, rows, err := query(e, stmt)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	want := []sql.Row{{int64(1), 1.5}, {int64(3), 2.5}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Query() rows = %v, want %v", rows, want)
+	}
+}
+
+func TestCSVStorage_ColumnOrder(t *testing.T) {
+	dir := t.TempDir()
+	content := "c,b,a\nx,1.5,1\n"
+	if err := os.WriteFile(filepath.Join(dir, "t1.csv"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := sql.NewParser(strings.NewReader(`SELECT * FROM t1`)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := sql.NewPlanner(&mockCatalog{})
+	plan, err := p.Plan(stmt)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	planned, err := p.Columns(plan)
+	if err != nil {
+		t.Fatalf("Columns() error = %v", err)
+	}
+	var want []string
+	for _, c := range planned {
+		want = append(want, c.String())
+	}
+
+	cols, rows, err := query(sql.NewExecutor(&mockCatalog{}, sql.NewCSVStorage(dir)), stmt)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if !reflect.DeepEqual(cols, want) {
+		t.Errorf("Query() columns = %v, planned %v", cols, want)
+	}
+	if want := []sql.Row{{int64(1), 1.5, "x"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("Query() rows = %v, want %v", rows, want)
+	}
+}
+
+func TestCSVStorage_MissingFile(t *testing.T) {
+	s := sql.NewCSVStorage(t.TempDir())
+	if _, err := s.Scan(sql.Relation{Name: "missing"}); err == nil {
+		t.Errorf("Scan() expected error for missing file")
+	}
+}
+
+func scanAll(s sql.Storage, r sql.Relation) ([]string, []sql.Row, error) {
+	it, err := s.Scan(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := it.Open(); err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+
+	var cols []string
+	for _, c := range it.Columns() {
+		cols = append(cols, c.String())
+	}
+	var rows []sql.Row
+	for {
+		row, err := it.Next()
+		if err == io.EOF {
+			return cols, rows, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, row)
+	}
+}
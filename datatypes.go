@@ -1,5 +1,11 @@
 package sql
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 type DataType int
 
 const (
@@ -28,3 +34,77 @@ func (d DataType) String() string {
 	}
 	return "N/A"
 }
+
+// typeAliases are the other names of the types in statements.
+var typeAliases = map[string]DataType{
+	"INT":       INTEGER,
+	"FLOAT":     REAL,
+	"DOUBLE":    REAL,
+	"VARCHAR":   TEXT,
+	"BOOL":      BOOLEAN,
+	"TIMESTAMP": DATETIME,
+	"DATE":      DATETIME,
+}
+
+// lookupDataType finds a type by its case insensitive name, or one of its aliases.
+func lookupDataType(name string) (DataType, bool) {
+	name = strings.ToUpper(name)
+	if t, ok := typeAliases[name]; ok {
+		return t, true
+	}
+	for t, str := range dataTypes {
+		if str == name && t != NULL {
+			return t, true
+		}
+	}
+	return NULL, false
+}
+
+// numericType is the type of the result of an arithmetic operation between values of the given types.
+// INTEGER is promoted to REAL, NULL is returned when an operand is not numeric.
+func numericType(a, b DataType) DataType {
+	switch {
+	case a == INTEGER && b == INTEGER:
+		return INTEGER
+	case (a == INTEGER || a == REAL) && (b == INTEGER || b == REAL):
+		return REAL
+	default:
+		return NULL
+	}
+}
+
+// commonType is the type the values of the given types can all be implicitly converted to.
+// NULL is compatible with any type.
+func commonType(types ...DataType) (DataType, error) {
+	common := NULL
+	for _, t := range types {
+		switch {
+		case implicitCast(t, common):
+		case implicitCast(common, t):
+			common = t
+		default:
+			return NULL, fmt.Errorf("types %s and %s cannot be matched", common, t)
+		}
+	}
+	return common, nil
+}
+
+// valueType is the type of a value.
+func valueType(v Value) DataType {
+	switch v.(type) {
+	case int64:
+		return INTEGER
+	case float64:
+		return REAL
+	case string:
+		return TEXT
+	case bool:
+		return BOOLEAN
+	case []byte:
+		return BLOB
+	case time.Time:
+		return DATETIME
+	default:
+		return NULL
+	}
+}
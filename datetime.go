@@ -0,0 +1,191 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// datetimeLayouts are the ISO-8601 forms accepted for DATETIME values. A fractional part is accepted
+// after the seconds and the values without time zone are in UTC.
+var datetimeLayouts = []string{
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseDatetime parses an ISO-8601 date or timestamp, the result is in UTC.
+func parseDatetime(s string) (time.Time, error) {
+	for _, layout := range datetimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid DATETIME \"%s\"", s)
+}
+
+// datetimeFunctions compute and transform DATETIME values, the units and fields are named case insensitively.
+// NOW is evaluated each time it is called.
+var datetimeFunctions = []Function{
+	{Name: "NOW", Returns: DATETIME, Call: func([]Value) (Value, error) {
+		return time.Now().UTC(), nil
+	}},
+	{Name: "DATE_TRUNC", Args: []DataType{TEXT, DATETIME}, Returns: DATETIME, Call: func(args []Value) (Value, error) {
+		return truncDatetime(args[0].(string), args[1].(time.Time))
+	}},
+	{Name: "EXTRACT", Args: []DataType{TEXT, DATETIME}, Returns: INTEGER, Call: func(args []Value) (Value, error) {
+		return extractDatetime(args[0].(string), args[1].(time.Time))
+	}},
+	{Name: "DATE_ADD", Args: []DataType{DATETIME, INTEGER, TEXT}, Returns: DATETIME, Call: func(args []Value) (Value, error) {
+		return addDatetime(args[0].(time.Time), args[1].(int64), args[2].(string))
+	}},
+	{Name: "STRFTIME", Args: []DataType{TEXT, DATETIME}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return strftime(args[0].(string), args[1].(time.Time))
+	}},
+}
+
+func init() {
+	registerBuiltins(datetimeFunctions)
+}
+
+// truncDatetime sets the fields of t smaller than the unit to their zero value, weeks start on Monday.
+func truncDatetime(unit string, t time.Time) (Value, error) {
+	y, m, d := t.Date()
+	switch strings.ToLower(unit) {
+	case "second":
+		return t.Truncate(time.Second), nil
+	case "minute":
+		return t.Truncate(time.Minute), nil
+	case "hour":
+		return t.Truncate(time.Hour), nil
+	case "day":
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location()), nil
+	case "week":
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location()), nil
+	case "month":
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location()), nil
+	case "quarter":
+		return time.Date(y, m-(m-1)%3, 1, 0, 0, 0, 0, t.Location()), nil
+	case "year":
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, t.Location()), nil
+	default:
+		return nil, fmt.Errorf("unknown DATE_TRUNC unit \"%s\"", unit)
+	}
+}
+
+// extractDatetime returns a field of t. The day of the week (dow) counts from Sunday as 0, the week
+// is the ISO-8601 week number and epoch the number of seconds since 1970-01-01 00:00:00 UTC.
+func extractDatetime(field string, t time.Time) (Value, error) {
+	var v int
+	switch strings.ToLower(field) {
+	case "year":
+		v = t.Year()
+	case "quarter":
+		v = (int(t.Month())-1)/3 + 1
+	case "month":
+		v = int(t.Month())
+	case "week":
+		_, v = t.ISOWeek()
+	case "day":
+		v = t.Day()
+	case "dow":
+		v = int(t.Weekday())
+	case "doy":
+		v = t.YearDay()
+	case "hour":
+		v = t.Hour()
+	case "minute":
+		v = t.Minute()
+	case "second":
+		v = t.Second()
+	case "epoch":
+		return t.Unix(), nil
+	default:
+		return nil, fmt.Errorf("unknown EXTRACT field \"%s\"", field)
+	}
+	return int64(v), nil
+}
+
+// addDatetime adds n units to t. Adding months or years keeps the day of the month,
+// unless the resulting month is shorter, then the result is its last day.
+func addDatetime(t time.Time, n int64, unit string) (Value, error) {
+	switch strings.ToLower(unit) {
+	case "second":
+		return t.Add(time.Duration(n) * time.Second), nil
+	case "minute":
+		return t.Add(time.Duration(n) * time.Minute), nil
+	case "hour":
+		return t.Add(time.Duration(n) * time.Hour), nil
+	case "day":
+		return t.AddDate(0, 0, int(n)), nil
+	case "week":
+		return t.AddDate(0, 0, 7*int(n)), nil
+	case "month":
+		return addMonths(t, int(n)), nil
+	case "quarter":
+		return addMonths(t, 3*int(n)), nil
+	case "year":
+		return addMonths(t, 12*int(n)), nil
+	default:
+		return nil, fmt.Errorf("unknown DATE_ADD unit \"%s\"", unit)
+	}
+}
+
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return first.AddDate(0, 0, d-1)
+}
+
+// strftime formats t with the conversions of SQLite:
+// %Y year, %m month, %d day, %H hour, %M minute, %S seconds, %f seconds with milliseconds (SS.SSS),
+// %j day of the year, %w day of the week from Sunday as 0, %s seconds since the epoch and %% a percent sign.
+func strftime(format string, t time.Time) (Value, error) {
+	var sb strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			sb.WriteByte(format[i])
+			continue
+		}
+		if i++; i == len(format) {
+			return nil, errors.New("STRFTIME format must not end with %")
+		}
+		switch format[i] {
+		case 'Y':
+			fmt.Fprintf(&sb, "%04d", t.Year())
+		case 'm':
+			fmt.Fprintf(&sb, "%02d", int(t.Month()))
+		case 'd':
+			fmt.Fprintf(&sb, "%02d", t.Day())
+		case 'H':
+			fmt.Fprintf(&sb, "%02d", t.Hour())
+		case 'M':
+			fmt.Fprintf(&sb, "%02d", t.Minute())
+		case 'S':
+			fmt.Fprintf(&sb, "%02d", t.Second())
+		case 'f':
+			fmt.Fprintf(&sb, "%02d.%03d", t.Second(), t.Nanosecond()/int(time.Millisecond))
+		case 'j':
+			fmt.Fprintf(&sb, "%03d", t.YearDay())
+		case 'w':
+			sb.WriteString(strconv.Itoa(int(t.Weekday())))
+		case 's':
+			sb.WriteString(strconv.FormatInt(t.Unix(), 10))
+		case '%':
+			sb.WriteByte('%')
+		default:
+			return nil, fmt.Errorf("unknown STRFTIME conversion %%%c", format[i])
+		}
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,61 @@
+package sql_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	sql "github.com/ndilsou/go-rdbms-playground"
+)
+
+func TestDatetimeFunctions(t *testing.T) {
+	cols := []sql.ResultColumn{{Table: "t", Name: "at", Type: sql.DATETIME}, {Table: "t", Name: "n", Type: sql.DATETIME}}
+	row := sql.Row{time.Date(2024, 8, 31, 13, 45, 30, 250000000, time.UTC), nil}
+
+	str := func(v string) sql.Expr { return &sql.BasicLit{Kind: sql.STRING, Value: v} }
+	num := func(v string) sql.Expr { return &sql.BasicLit{Kind: sql.INT, Value: v} }
+	call := func(name string, args ...sql.Expr) sql.Expr { return &sql.CallExpr{Name: name, Args: args} }
+	at, n := &sql.QualifiedName{Column: "at"}, &sql.QualifiedName{Column: "n"}
+	date := func(y int, m time.Month, d, h, min, s int) time.Time {
+		return time.Date(y, m, d, h, min, s, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		expr    sql.Expr
+		want    sql.Value
+		wantErr bool
+	}{
+		{name: "date literal", expr: &sql.BasicLit{Kind: sql.DATE, Value: "2024-02-29"}, want: date(2024, 2, 29, 0, 0, 0)},
+		{name: "timestamp literal", expr: &sql.BasicLit{Kind: sql.TIMESTAMP, Value: "2024-02-29T10:00:00-02:00"}, want: date(2024, 2, 29, 12, 0, 0)},
+		{name: "invalid date literal", expr: &sql.BasicLit{Kind: sql.DATE, Value: "2023-02-29"}, wantErr: true},
+		{name: "truncate to hour", expr: call("DATE_TRUNC", str("hour"), at), want: date(2024, 8, 31, 13, 0, 0)},
+		{name: "truncate to week", expr: call("DATE_TRUNC", str("WEEK"), at), want: date(2024, 8, 26, 0, 0, 0)},
+		{name: "truncate to quarter", expr: call("DATE_TRUNC", str("quarter"), at), want: date(2024, 7, 1, 0, 0, 0)},
+		{name: "truncate null", expr: call("DATE_TRUNC", str("day"), n), want: nil},
+		{name: "truncate unknown unit", expr: call("DATE_TRUNC", str("fortnight"), at), wantErr: true},
+		{name: "extract day of week", expr: call("EXTRACT", str("dow"), at), want: int64(6)},
+		{name: "extract day of year", expr: call("EXTRACT", str("doy"), at), want: int64(244)},
+		{name: "extract epoch", expr: call("EXTRACT", str("epoch"), at), want: int64(1725111930)},
+		{name: "extract unknown field", expr: call("EXTRACT", str("century"), at), wantErr: true},
+		{name: "add days", expr: call("DATE_ADD", at, num("-31"), str("day")), want: time.Date(2024, 7, 31, 13, 45, 30, 250000000, time.UTC)},
+		{name: "add months to a longer month", expr: call("DATE_ADD", at, num("6"), str("month")), want: time.Date(2025, 2, 28, 13, 45, 30, 250000000, time.UTC)},
+		{name: "add years", expr: call("DATE_ADD", &sql.BasicLit{Kind: sql.DATE, Value: "2024-02-29"}, num("1"), str("year")), want: date(2025, 2, 28, 0, 0, 0)},
+		{name: "add unknown unit", expr: call("DATE_ADD", at, num("1"), str("century")), wantErr: true},
+		{name: "format", expr: call("STRFTIME", str("%Y-%m-%d %H:%M:%f %j %w %%"), at), want: "2024-08-31 13:45:30.250 244 6 %"},
+		{name: "format unknown conversion", expr: call("STRFTIME", str("%Q"), at), wantErr: true},
+		{name: "compare", expr: &sql.BinaryExpr{LHS: at, Op: sql.GT, RHS: &sql.BasicLit{Kind: sql.DATE, Value: "2024-08-31"}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := eval(tt.expr, cols, row)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Eval() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eval() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package sql
+
+// compileDistinct deduplicates the rows as they come when its input is sorted on all of its columns,
+// duplicates are then next to each other. Otherwise the rows already returned are hashed.
+func (e Executor) compileDistinct(plan PlanNode, sorted bool) (Iterator, error) {
+	from, err := e.Compile(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	if sorted {
+		return &sortedDistinctIterator{from: from}, nil
+	}
+	return &hashDistinctIterator{from: from}, nil
+}
+
+// hashDistinctIterator returns the first occurrence of each row, keeping the order of its input.
+// As for groups, NULL values are not distinct from each other.
+type hashDistinctIterator struct {
+	from Iterator
+	seen map[string]struct{}
+}
+
+func (it *hashDistinctIterator) Columns() []ResultColumn { return it.from.Columns() }
+
+func (it *hashDistinctIterator) Open() error {
+	it.seen = make(map[string]struct{})
+	return it.from.Open()
+}
+
+func (it *hashDistinctIterator) Next() (Row, error) {
+	for {
+		row, err := it.from.Next()
+		if err != nil {
+			return nil, err
+		}
+		key := groupKey(row)
+		if _, ok := it.seen[key]; ok {
+			continue
+		}
+		it.seen[key] = struct{}{}
+		return row, nil
+	}
+}
+
+func (it *hashDistinctIterator) Close() error {
+	it.seen = nil
+	return it.from.Close()
+}
+
+// sortedDistinctIterator skips the rows equal to the previous one, its input must be sorted on all columns.
+type sortedDistinctIterator struct {
+	from Iterator
+	last Row
+}
+
+func (it *sortedDistinctIterator) Columns() []ResultColumn { return it.from.Columns() }
+
+func (it *sortedDistinctIterator) Open() error {
+	it.last = nil
+	return it.from.Open()
+}
+
+func (it *sortedDistinctIterator) Next() (Row, error) {
+	for {
+		row, err := it.from.Next()
+		if err != nil {
+			return nil, err
+		}
+		if it.last != nil {
+			same, err := sameKeys(it.last, row)
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+		}
+		it.last = row
+		return row, nil
+	}
+}
+
+func (it *sortedDistinctIterator) Close() error {
+	it.last = nil
+	return it.from.Close()
+}
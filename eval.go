@@ -0,0 +1,634 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Evaluator computes the value of an expression for the rows of a given set of columns.
+//
+// Predicates follow SQL three-valued logic: they evaluate to true, false or UNKNOWN,
+// UNKNOWN being represented by a NULL (nil) value. Comparisons involving NULL are UNKNOWN
+// and AND/OR only propagate UNKNOWN when the other operand does not decide the result.
+type Evaluator struct {
+	eval evalFunc
+}
+
+// NewEvaluator resolves the identifiers of the expression against the columns.
+func NewEvaluator(expr Expr, cols []ResultColumn) (*Evaluator, error) {
+	fn, err := compileExpr(expr, cols)
+	if err != nil {
+		return nil, err
+	}
+	return &Evaluator{eval: fn}, nil
+}
+
+// Eval computes the value of the expression for a row.
+func (e *Evaluator) Eval(row Row) (Value, error) {
+	return e.eval(row)
+}
+
+// evalFunc computes the value of an expression for a row.
+type evalFunc func(row Row) (Value, error)
+
+// compileExpr resolves the identifiers of an expression against the columns of a row
+// and returns a function evaluating it.
+func compileExpr(expr Expr, cols []ResultColumn) (evalFunc, error) {
+	switch e := expr.(type) {
+	case *QualifiedName:
+		i, err := resolveColumn(cols, e)
+		if err != nil {
+			return nil, err
+		}
+		return columnValue(i), nil
+	case *AggregateExpr:
+		i, err := aggregateColumn(cols, e)
+		if err != nil {
+			return nil, err
+		}
+		return columnValue(i), nil
+	case *BasicLit:
+		v, err := literalValue(e)
+		if err != nil {
+			return nil, err
+		}
+		return func(Row) (Value, error) { return v, nil }, nil
+	case *UnaryExpr:
+		return compileUnaryExpr(e, cols)
+	case *BinaryExpr:
+		return compileBinaryExpr(e, cols)
+	case *InExpr:
+		return compileInExpr(e, cols)
+	case *BetweenExpr:
+		return compileBetweenExpr(e, cols)
+	case *LikeExpr:
+		return compileLikeExpr(e, cols)
+	case *CaseExpr:
+		return compileCaseExpr(e, cols)
+	case *CastExpr:
+		return compileCastExpr(e, cols)
+	case *CallExpr:
+		return compileCallExpr(e, cols)
+	case *IsNullExpr:
+		x, err := compileExpr(e.X, cols)
+		if err != nil {
+			return nil, err
+		}
+		not := e.Not
+		return func(row Row) (Value, error) {
+			v, err := x(row)
+			if err != nil {
+				return nil, err
+			}
+			return (v == nil) != not, nil
+		}, nil
+	case nil:
+		return nil, errors.New("invalid expression: missing operand")
+	default:
+		return nil, fmt.Errorf("cannot evaluate expression %T", expr)
+	}
+}
+
+// compileColumn compiles an expression computing a column and describes the resulting column.
+func compileColumn(expr Expr, cols []ResultColumn) (evalFunc, ResultColumn, error) {
+	switch e := expr.(type) {
+	case *QualifiedName:
+		i, err := resolveColumn(cols, e)
+		if err != nil {
+			return nil, ResultColumn{}, err
+		}
+		return columnValue(i), cols[i], nil
+	case *AggregateExpr:
+		i, err := aggregateColumn(cols, e)
+		if err != nil {
+			return nil, ResultColumn{}, err
+		}
+		return columnValue(i), cols[i], nil
+	case *AliasExpr:
+		fn, col, err := compileColumn(e.Expr, cols)
+		if err != nil {
+			return nil, ResultColumn{}, err
+		}
+		return fn, ResultColumn{Name: e.Alias.Name, Type: col.Type}, nil
+	default:
+		fn, err := compileExpr(expr, cols)
+		if err != nil {
+			return nil, ResultColumn{}, err
+		}
+		return fn, ResultColumn{Name: fmt.Sprint(expr), Type: exprType(expr, cols)}, nil
+	}
+}
+
+// aggregateColumn finds the column holding the result of an aggregate.
+// Aggregates are computed by the aggregate operators, their results are columns named after them.
+func aggregateColumn(cols []ResultColumn, e *AggregateExpr) (int, error) {
+	name := e.String()
+	for i, c := range cols {
+		if c.Table == "" && c.Name == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("aggregate %s is not computed at this stage", name)
+}
+
+// columnValue returns a function reading the value at a position of the row.
+func columnValue(i int) evalFunc {
+	return func(row Row) (Value, error) { return row[i], nil }
+}
+
+// exprType infers the type of the values of an expression, NULL when it cannot be known in advance.
+func exprType(expr Expr, cols []ResultColumn) DataType {
+	t, _ := checkExpr(expr, cols, nil)
+	return t
+}
+
+func compileUnaryExpr(e *UnaryExpr, cols []ResultColumn) (evalFunc, error) {
+	x, err := compileExpr(e.X, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.Op {
+	case MINUS, PLUS:
+		op := e.Op
+		return func(row Row) (Value, error) {
+			v, err := x(row)
+			if err != nil || v == nil {
+				return nil, err
+			}
+			return evalSign(op, v)
+		}, nil
+	case NOT:
+		return func(row Row) (Value, error) {
+			v, err := evalBool(row, x)
+			if err != nil || v == nil {
+				return nil, err
+			}
+			return !v.(bool), nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported unary operator %s", e.Op)
+	}
+}
+
+// evalSign applies an unary minus or plus to a non NULL value.
+func evalSign(op Token, v Value) (Value, error) {
+	switch x := v.(type) {
+	case int64:
+		if op == PLUS {
+			return x, nil
+		}
+		if x == math.MinInt64 {
+			return nil, fmt.Errorf("INTEGER out of range: -(%d)", x)
+		}
+		return -x, nil
+	case float64:
+		if op == PLUS {
+			return x, nil
+		}
+		return -x, nil
+	default:
+		return nil, fmt.Errorf("cannot apply %s to %v", op.Symbol(), v)
+	}
+}
+
+func compileBinaryExpr(e *BinaryExpr, cols []ResultColumn) (evalFunc, error) {
+	lhs, err := compileExpr(e.LHS, cols)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := compileExpr(e.RHS, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	op := e.Op
+	switch {
+	case op == AND:
+		return func(row Row) (Value, error) { return evalLogical(row, lhs, rhs, false) }, nil
+	case op == OR:
+		return func(row Row) (Value, error) { return evalLogical(row, lhs, rhs, true) }, nil
+	case op.IsArithmeticOperator():
+		return func(row Row) (Value, error) {
+			l, err := lhs(row)
+			if err != nil {
+				return nil, err
+			}
+			r, err := rhs(row)
+			if err != nil {
+				return nil, err
+			}
+			if l == nil || r == nil {
+				return nil, nil
+			}
+			return evalArithmetic(op, l, r)
+		}, nil
+	case op.IsComparisonOperator():
+		lt, rt := exprType(e.LHS, cols), exprType(e.RHS, cols)
+		t, err := comparisonType(lt, rt)
+		if err != nil {
+			return nil, err
+		}
+		lhs, rhs = implicitCoerce(lhs, lt, t), implicitCoerce(rhs, rt, t)
+		return func(row Row) (Value, error) {
+			l, err := lhs(row)
+			if err != nil {
+				return nil, err
+			}
+			r, err := rhs(row)
+			if err != nil {
+				return nil, err
+			}
+			if l == nil || r == nil {
+				return nil, nil
+			}
+			c, err := compareValues(l, r)
+			if err != nil {
+				return nil, err
+			}
+			return compareResult(op, c), nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", op)
+	}
+}
+
+// compileCaseExpr evaluates the result of the first WHEN clause whose condition is true, or which is equal
+// to the operand of a simple CASE. The results are implicitly converted to the common type of all the branches.
+// The operand of a simple CASE is evaluated once, and converted for each WHEN value as for a comparison.
+func compileCaseExpr(e *CaseExpr, cols []ResultColumn) (evalFunc, error) {
+	typ, err := caseType(e, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	var operand evalFunc
+	var operandType DataType
+	if e.Operand != nil {
+		if operand, err = compileExpr(e.Operand, cols); err != nil {
+			return nil, err
+		}
+		operandType = exprType(e.Operand, cols)
+	}
+	conds := make([]evalFunc, len(e.Whens))
+	results := make([]evalFunc, len(e.Whens))
+	// casts holds the type the operand is converted to before being compared to each WHEN value, NULL if none.
+	casts := make([]DataType, len(e.Whens))
+	for i, w := range e.Whens {
+		if conds[i], err = compileExpr(w.Cond, cols); err != nil {
+			return nil, err
+		}
+		if results[i], err = compileExpr(w.Result, cols); err != nil {
+			return nil, err
+		}
+		if operand != nil {
+			condType := exprType(w.Cond, cols)
+			t, err := comparisonType(operandType, condType)
+			if err != nil {
+				return nil, err
+			}
+			conds[i] = implicitCoerce(conds[i], condType, t)
+			if needsCoercion(operandType, t) {
+				casts[i] = t
+			}
+		}
+	}
+	otherwise := func(Row) (Value, error) { return nil, nil }
+	if e.Else != nil {
+		if otherwise, err = compileExpr(e.Else, cols); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(row Row) (Value, error) {
+		var x Value
+		if operand != nil {
+			v, err := operand(row)
+			if err != nil {
+				return nil, err
+			}
+			x = v
+		}
+
+		result := otherwise
+		for i, cond := range conds {
+			v, err := cond(row)
+			if err != nil {
+				return nil, err
+			}
+			if operand != nil {
+				if x == nil || v == nil {
+					continue
+				}
+				x := x
+				if casts[i] != NULL {
+					if x, err = castValue(x, casts[i]); err != nil {
+						return nil, err
+					}
+				}
+				c, err := compareValues(x, v)
+				if err != nil {
+					return nil, err
+				}
+				v = c == 0
+			}
+			if isTrue(v) {
+				result = results[i]
+				break
+			}
+		}
+
+		v, err := result(row)
+		if err != nil {
+			return nil, err
+		}
+		if typ == NULL {
+			return v, nil
+		}
+		return castValue(v, typ)
+	}, nil
+}
+
+// caseType is the common type of the results of a CASE expression.
+func caseType(e *CaseExpr, cols []ResultColumn) (DataType, error) {
+	var types []DataType
+	for _, w := range e.Whens {
+		types = append(types, exprType(w.Result, cols))
+	}
+	if e.Else != nil {
+		types = append(types, exprType(e.Else, cols))
+	}
+	t, err := commonType(types...)
+	if err != nil {
+		return NULL, fmt.Errorf("CASE %w", err)
+	}
+	return t, nil
+}
+
+// evalLogical evaluates AND (when decisive is false) and OR (when decisive is true).
+// The right operand is not evaluated when the left one decides the result.
+func evalLogical(row Row, lhs, rhs evalFunc, decisive bool) (Value, error) {
+	l, err := evalBool(row, lhs)
+	if err != nil {
+		return nil, err
+	}
+	if l != nil && l.(bool) == decisive {
+		return decisive, nil
+	}
+
+	r, err := evalBool(row, rhs)
+	if err != nil {
+		return nil, err
+	}
+	if r != nil && r.(bool) == decisive {
+		return decisive, nil
+	}
+	if l == nil || r == nil {
+		return nil, nil
+	}
+	return !decisive, nil
+}
+
+// evalBool evaluates an operand of a logical operator, it must be a BOOLEAN or NULL.
+func evalBool(row Row, fn evalFunc) (Value, error) {
+	v, err := fn(row)
+	if err != nil {
+		return nil, err
+	}
+	switch v.(type) {
+	case nil, bool:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("expected BOOLEAN operand, got %v", v)
+	}
+}
+
+// evalArithmetic applies an arithmetic or concatenation operator to non NULL values.
+// Operations between INTEGER values give an INTEGER, an INTEGER is promoted to REAL
+// when the other operand is a REAL.
+func evalArithmetic(op Token, l, r Value) (Value, error) {
+	if op == CONCAT {
+		x, ok := l.(string)
+		y, ok2 := r.(string)
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("cannot concatenate %v and %v", l, r)
+		}
+		return x + y, nil
+	}
+
+	switch x := l.(type) {
+	case int64:
+		switch y := r.(type) {
+		case int64:
+			return intArithmetic(op, x, y)
+		case float64:
+			return realArithmetic(op, float64(x), y)
+		}
+	case float64:
+		switch y := r.(type) {
+		case int64:
+			return realArithmetic(op, x, float64(y))
+		case float64:
+			return realArithmetic(op, x, y)
+		}
+	}
+	return nil, fmt.Errorf("cannot apply %s to %v and %v", op.Symbol(), l, r)
+}
+
+// intArithmetic computes an operation on INTEGER values, the division truncates toward zero.
+func intArithmetic(op Token, x, y int64) (Value, error) {
+	var z int64
+	overflow := false
+	switch op {
+	case PLUS:
+		z = x + y
+		overflow = (y > 0 && z < x) || (y < 0 && z > x)
+	case MINUS:
+		z = x - y
+		overflow = (y > 0 && z > x) || (y < 0 && z < x)
+	case ASTERISK:
+		z = x * y
+		overflow = x != 0 && (z/x != y || (x == -1 && y == math.MinInt64))
+	case SLASH, PERCENT:
+		if y == 0 {
+			return nil, errors.New("division by zero")
+		}
+		if op == PERCENT {
+			return x % y, nil
+		}
+		z = x / y
+		overflow = x == math.MinInt64 && y == -1
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", op)
+	}
+	if overflow {
+		return nil, fmt.Errorf("INTEGER out of range: %d %s %d", x, op.Symbol(), y)
+	}
+	return z, nil
+}
+
+// realArithmetic computes an operation on REAL values.
+func realArithmetic(op Token, x, y float64) (Value, error) {
+	switch op {
+	case PLUS:
+		return x + y, nil
+	case MINUS:
+		return x - y, nil
+	case ASTERISK:
+		return x * y, nil
+	case SLASH, PERCENT:
+		if y == 0 {
+			return nil, errors.New("division by zero")
+		}
+		if op == PERCENT {
+			return math.Mod(x, y), nil
+		}
+		return x / y, nil
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", op)
+	}
+}
+
+// isTrue reports whether a predicate value is true, false and UNKNOWN are both rejected.
+func isTrue(v Value) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+func compareResult(op Token, c int) bool {
+	switch op {
+	case EQ:
+		return c == 0
+	case NEQ:
+		return c != 0
+	case LT:
+		return c < 0
+	case LTE:
+		return c <= 0
+	case GT:
+		return c > 0
+	case GTE:
+		return c >= 0
+	}
+	return false
+}
+
+// literalValue converts a literal into its value.
+func literalValue(l *BasicLit) (Value, error) {
+	switch l.Kind {
+	case INT:
+		v, err := strconv.ParseInt(l.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid INT literal \"%s\"", l.Value)
+		}
+		return v, nil
+	case FLOAT:
+		v, err := strconv.ParseFloat(l.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FLOAT literal \"%s\"", l.Value)
+		}
+		return v, nil
+	case STRING:
+		return l.Value, nil
+	case NULLLIT:
+		return nil, nil
+	case DATE:
+		v, err := time.Parse("2006-01-02", l.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DATE literal \"%s\"", l.Value)
+		}
+		return v, nil
+	case TIMESTAMP:
+		v, err := parseDatetime(l.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TIMESTAMP literal \"%s\"", l.Value)
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("invalid literal \"%s\"", l.Value)
+	}
+}
+
+// compareValues orders two values, NULL sorts before any other value.
+func compareValues(a, b Value) (int, error) {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0, nil
+		case a == nil:
+			return -1, nil
+		default:
+			return 1, nil
+		}
+	}
+
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return compareInts(x, y), nil
+		case float64:
+			return compareFloats(float64(x), y), nil
+		}
+	case float64:
+		switch y := b.(type) {
+		case int64:
+			return compareFloats(x, float64(y)), nil
+		case float64:
+			return compareFloats(x, y), nil
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			switch {
+			case x.Before(y):
+				return -1, nil
+			case x.After(y):
+				return 1, nil
+			default:
+				return 0, nil
+			}
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, nil
+			case !x:
+				return -1, nil
+			default:
+				return 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %v and %v", a, b)
+}
+
+func compareInts(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareFloats(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,193 @@
+package sql_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	sql "github.com/ndilsou/go-rdbms-playground"
+)
+
+func TestEvaluator_Eval(t *testing.T) {
+	cols := []sql.ResultColumn{
+		{Table: "t1", Name: "a", Type: sql.INTEGER},
+		{Table: "t1", Name: "b", Type: sql.REAL},
+		{Table: "t1", Name: "c", Type: sql.TEXT},
+		{Table: "t1", Name: "n", Type: sql.INTEGER},
+	}
+	row := sql.Row{int64(1), 2.5, "x", nil}
+
+	column := func(name string) sql.Expr { return &sql.QualifiedName{Column: name} }
+	lit := func(kind sql.Token, v string) sql.Expr { return &sql.BasicLit{Kind: kind, Value: v} }
+	bin := func(lhs sql.Expr, op sql.Token, rhs sql.Expr) sql.Expr {
+		return &sql.BinaryExpr{LHS: lhs, Op: op, RHS: rhs}
+	}
+	cast := func(x sql.Expr, t sql.DataType) sql.Expr { return &sql.CastExpr{X: x, Type: t} }
+	call := func(name string, args ...sql.Expr) sql.Expr { return &sql.CallExpr{Name: name, Args: args} }
+	isTrue := bin(column("a"), sql.EQ, lit(sql.INT, "1"))
+	isFalse := bin(column("a"), sql.EQ, lit(sql.INT, "2"))
+	isUnknown := bin(column("n"), sql.EQ, lit(sql.INT, "1"))
+
+	tests := []struct {
+		name    string
+		expr    sql.Expr
+		want    sql.Value
+		wantErr bool
+	}{
+		{name: "column", expr: column("c"), want: "x"},
+		{name: "qualified column", expr: &sql.QualifiedName{Table: "t1", Column: "b"}, want: 2.5},
+		{name: "int literal", expr: lit(sql.INT, "42"), want: int64(42)},
+		{name: "smallest integer literal", expr: lit(sql.INT, "-9223372036854775808"), want: int64(math.MinInt64)},
+		{name: "float literal", expr: lit(sql.FLOAT, "0.5"), want: 0.5},
+		{name: "string literal", expr: lit(sql.STRING, "abc"), want: "abc"},
+		{name: "equal", expr: isTrue, want: true},
+		{name: "not equal", expr: bin(column("c"), sql.NEQ, lit(sql.STRING, "x")), want: false},
+		{name: "int and real", expr: bin(column("a"), sql.LT, column("b")), want: true},
+		{name: "real and int literal", expr: bin(column("b"), sql.GTE, lit(sql.INT, "3")), want: false},
+		{name: "lower or equal", expr: bin(column("a"), sql.LTE, lit(sql.FLOAT, "1.0")), want: true},
+		{name: "greater", expr: bin(column("c"), sql.GT, lit(sql.STRING, "a")), want: true},
+		{name: "null comparison", expr: isUnknown, want: nil},
+		{name: "null equals null", expr: bin(column("n"), sql.EQ, column("n")), want: nil},
+		{name: "true and unknown", expr: bin(isTrue, sql.AND, isUnknown), want: nil},
+		{name: "false and unknown", expr: bin(isFalse, sql.AND, isUnknown), want: false},
+		{name: "unknown and false", expr: bin(isUnknown, sql.AND, isFalse), want: false},
+		{name: "true and true", expr: bin(isTrue, sql.AND, isTrue), want: true},
+		{name: "true or unknown", expr: bin(isTrue, sql.OR, isUnknown), want: true},
+		{name: "unknown or true", expr: bin(isUnknown, sql.OR, isTrue), want: true},
+		{name: "false or unknown", expr: bin(isFalse, sql.OR, isUnknown), want: nil},
+		{name: "false or false", expr: bin(isFalse, sql.OR, isFalse), want: false},
+		{name: "integer addition", expr: bin(column("a"), sql.PLUS, lit(sql.INT, "41")), want: int64(42)},
+		{name: "real promotion", expr: bin(column("a"), sql.ASTERISK, column("b")), want: 2.5},
+		{name: "integer division", expr: bin(lit(sql.INT, "7"), sql.SLASH, lit(sql.INT, "2")), want: int64(3)},
+		{name: "real division", expr: bin(column("b"), sql.SLASH, lit(sql.INT, "2")), want: 1.25},
+		{name: "modulo", expr: bin(lit(sql.INT, "-7"), sql.PERCENT, lit(sql.INT, "3")), want: int64(-1)},
+		{name: "precedence", expr: bin(column("a"), sql.MINUS, bin(column("b"), sql.ASTERISK, lit(sql.INT, "2"))), want: -4.0},
+		{name: "null arithmetic", expr: bin(column("n"), sql.PLUS, lit(sql.INT, "1")), want: nil},
+		{name: "unary minus", expr: &sql.UnaryExpr{Op: sql.MINUS, X: column("b")}, want: -2.5},
+		{name: "unary minus null", expr: &sql.UnaryExpr{Op: sql.MINUS, X: column("n")}, want: nil},
+		{name: "concatenation", expr: bin(column("c"), sql.CONCAT, lit(sql.STRING, "yz")), want: "xyz"},
+		{name: "division by zero", expr: bin(column("a"), sql.SLASH, lit(sql.INT, "0")), wantErr: true},
+		{name: "integer overflow", expr: bin(lit(sql.INT, "9223372036854775807"), sql.PLUS, column("a")), wantErr: true},
+		{name: "non numeric operand", expr: bin(column("c"), sql.PLUS, lit(sql.INT, "1")), wantErr: true},
+		{name: "non text concatenation", expr: bin(column("c"), sql.CONCAT, column("a")), wantErr: true},
+		{name: "null literal", expr: lit(sql.NULLLIT, "NULL"), want: nil},
+		{name: "not true", expr: &sql.UnaryExpr{Op: sql.NOT, X: isTrue}, want: false},
+		{name: "not false", expr: &sql.UnaryExpr{Op: sql.NOT, X: isFalse}, want: true},
+		{name: "not unknown", expr: &sql.UnaryExpr{Op: sql.NOT, X: isUnknown}, want: nil},
+		{name: "is null", expr: &sql.IsNullExpr{X: column("n")}, want: true},
+		{name: "is null on value", expr: &sql.IsNullExpr{X: column("a")}, want: false},
+		{name: "is not null", expr: &sql.IsNullExpr{X: column("n"), Not: true}, want: false},
+		{name: "is null on unknown", expr: &sql.IsNullExpr{X: isUnknown}, want: true},
+		{name: "null literal is null", expr: &sql.IsNullExpr{X: lit(sql.NULLLIT, "NULL")}, want: true},
+		{name: "non boolean negation", expr: &sql.UnaryExpr{Op: sql.NOT, X: column("a")}, wantErr: true},
+		{name: "in", expr: &sql.InExpr{X: column("a"), List: []sql.Expr{lit(sql.INT, "3"), lit(sql.INT, "1")}}, want: true},
+		{name: "in without match", expr: &sql.InExpr{X: column("a"), List: []sql.Expr{lit(sql.INT, "3")}}, want: false},
+		{name: "in with null", expr: &sql.InExpr{X: column("a"), List: []sql.Expr{lit(sql.INT, "3"), column("n")}}, want: nil},
+		{name: "in with null and match", expr: &sql.InExpr{X: column("a"), List: []sql.Expr{column("n"), lit(sql.INT, "1")}}, want: true},
+		{name: "null in", expr: &sql.InExpr{X: column("n"), List: []sql.Expr{lit(sql.INT, "1")}}, want: nil},
+		{name: "not in", expr: &sql.InExpr{X: column("c"), List: []sql.Expr{lit(sql.STRING, "y")}, Not: true}, want: true},
+		{name: "not in with null", expr: &sql.InExpr{X: column("a"), List: []sql.Expr{column("n")}, Not: true}, want: nil},
+		{name: "between", expr: &sql.BetweenExpr{X: column("b"), Low: column("a"), High: lit(sql.INT, "3")}, want: true},
+		{name: "between bounds", expr: &sql.BetweenExpr{X: column("a"), Low: lit(sql.INT, "1"), High: lit(sql.INT, "1")}, want: true},
+		{name: "not between", expr: &sql.BetweenExpr{X: column("a"), Low: lit(sql.INT, "2"), High: lit(sql.INT, "3"), Not: true}, want: true},
+		{name: "between null bound", expr: &sql.BetweenExpr{X: column("a"), Low: column("n"), High: lit(sql.INT, "3")}, want: nil},
+		{name: "between null bound out of range", expr: &sql.BetweenExpr{X: column("a"), Low: column("n"), High: lit(sql.INT, "0")}, want: false},
+		{name: "like", expr: &sql.LikeExpr{X: column("c"), Pattern: lit(sql.STRING, "_")}, want: true},
+		{name: "not like", expr: &sql.LikeExpr{X: column("c"), Pattern: lit(sql.STRING, "y%"), Not: true}, want: true},
+		{name: "like with escape", expr: &sql.LikeExpr{X: lit(sql.STRING, "x_"), Pattern: lit(sql.STRING, "x!_"), Escape: lit(sql.STRING, "!")}, want: true},
+		{name: "like null", expr: &sql.LikeExpr{X: column("n"), Pattern: lit(sql.STRING, "%")}, want: nil},
+		{name: "like non text", expr: &sql.LikeExpr{X: column("a"), Pattern: lit(sql.STRING, "%")}, wantErr: true},
+		{name: "like invalid escape", expr: &sql.LikeExpr{X: column("c"), Pattern: lit(sql.STRING, "%"), Escape: lit(sql.STRING, "ab")}, wantErr: true},
+		{
+			name: "searched case",
+			expr: &sql.CaseExpr{
+				Whens: []*sql.WhenClause{{Cond: isUnknown, Result: lit(sql.INT, "1")}, {Cond: isTrue, Result: lit(sql.INT, "2")}},
+				Else:  lit(sql.INT, "3"),
+			},
+			want: int64(2),
+		},
+		{
+			name: "case without else",
+			expr: &sql.CaseExpr{Whens: []*sql.WhenClause{{Cond: isFalse, Result: lit(sql.INT, "1")}}},
+			want: nil,
+		},
+		{
+			name: "simple case",
+			expr: &sql.CaseExpr{
+				Operand: column("c"),
+				Whens:   []*sql.WhenClause{{Cond: lit(sql.STRING, "y"), Result: lit(sql.INT, "1")}, {Cond: lit(sql.STRING, "x"), Result: lit(sql.INT, "2")}},
+			},
+			want: int64(2),
+		},
+		{
+			name: "simple case on null",
+			expr: &sql.CaseExpr{
+				Operand: column("n"),
+				Whens:   []*sql.WhenClause{{Cond: lit(sql.NULLLIT, "NULL"), Result: lit(sql.STRING, "null")}},
+				Else:    lit(sql.STRING, "other"),
+			},
+			want: "other",
+		},
+		{
+			name: "case result promotion",
+			expr: &sql.CaseExpr{Whens: []*sql.WhenClause{{Cond: isTrue, Result: column("a")}}, Else: column("b")},
+			want: 1.0,
+		},
+		{
+			name:    "case incompatible results",
+			expr:    &sql.CaseExpr{Whens: []*sql.WhenClause{{Cond: isTrue, Result: column("a")}}, Else: column("c")},
+			wantErr: true,
+		},
+		{name: "function call", expr: call("abs", lit(sql.INT, "-3")), want: int64(3)},
+		{name: "function overload", expr: call("ABS", bin(lit(sql.INT, "0"), sql.MINUS, column("b"))), want: 2.5},
+		{name: "function argument promotion", expr: call("round", column("a"), lit(sql.INT, "2")), want: 1.0},
+		{name: "round to decimal places", expr: call("round", lit(sql.FLOAT, "123.456"), lit(sql.INT, "2")), want: 123.46},
+		{name: "round to tens", expr: call("round", lit(sql.FLOAT, "123.456"), lit(sql.INT, "-1")), want: 120.0},
+		{name: "round past the precision", expr: call("round", lit(sql.FLOAT, "2.5"), lit(sql.INT, "400")), want: 2.5},
+		{name: "round overflowing the scale", expr: call("round", lit(sql.FLOAT, "1e300"), lit(sql.INT, "10")), want: 1e300},
+		{name: "round underflowing the scale", expr: call("round", lit(sql.FLOAT, "123.456"), lit(sql.INT, "-400")), want: 0.0},
+		{name: "function of null", expr: call("abs", column("n")), want: nil},
+		{name: "function argument types", expr: call("abs", column("c")), wantErr: true},
+		{name: "function argument count", expr: call("abs"), wantErr: true},
+		{name: "unknown function", expr: call("nope", column("a")), wantErr: true},
+		{name: "cast text to integer", expr: cast(lit(sql.STRING, " 42 "), sql.INTEGER), want: int64(42)},
+		{name: "cast invalid text", expr: cast(column("c"), sql.INTEGER), wantErr: true},
+		{name: "cast text to boolean", expr: cast(lit(sql.STRING, "TRUE"), sql.BOOLEAN), want: true},
+		{name: "cast text to blob", expr: cast(column("c"), sql.BLOB), want: []byte("x")},
+		{name: "cast real to integer", expr: cast(column("b"), sql.INTEGER), want: int64(3)},
+		{name: "cast real to text", expr: cast(column("b"), sql.TEXT), want: "2.5"},
+		{name: "cast integer to boolean", expr: cast(column("a"), sql.BOOLEAN), want: true},
+		{name: "cast boolean to integer", expr: cast(isFalse, sql.INTEGER), want: int64(0)},
+		{name: "cast null", expr: cast(column("n"), sql.TEXT), want: nil},
+		{name: "cast datetime to text", expr: cast(lit(sql.TIMESTAMP, "2024-01-02 03:04:05"), sql.TEXT), want: "2024-01-02T03:04:05Z"},
+		{name: "invalid cast", expr: cast(column("b"), sql.BOOLEAN), wantErr: true},
+		{name: "implicit cast to datetime", expr: bin(lit(sql.DATE, "2024-01-02"), sql.GT, lit(sql.STRING, "2024-01-01 23:59:59")), want: true},
+		{name: "incompatible comparison", expr: bin(column("a"), sql.EQ, column("c")), wantErr: true},
+		{name: "implicit cast in case", expr: &sql.CaseExpr{Whens: []*sql.WhenClause{{Cond: isTrue, Result: lit(sql.STRING, "2024-01-01")}}, Else: lit(sql.DATE, "2024-01-02")}, want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "unknown column", expr: column("z"), wantErr: true},
+		{name: "incompatible comparison", expr: bin(column("c"), sql.EQ, lit(sql.INT, "1")), wantErr: true},
+		{name: "non boolean operand", expr: bin(column("a"), sql.AND, isTrue), wantErr: true},
+		{name: "invalid literal", expr: lit(sql.INT, "1.5"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := eval(tt.expr, cols, row)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Eval() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eval() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func eval(expr sql.Expr, cols []sql.ResultColumn, row sql.Row) (sql.Value, error) {
+	e, err := sql.NewEvaluator(expr, cols)
+	if err != nil {
+		return nil, err
+	}
+	return e.Eval(row)
+}
@@ -0,0 +1,446 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Value is a single SQL value.
+// NULL is represented by nil, INTEGER by int64, REAL by float64, TEXT by string,
+// DATETIME by time.Time, BOOLEAN by bool and BLOB by []byte.
+type Value interface{}
+
+// Row is a tuple of values produced by an Iterator.
+type Row []Value
+
+// ResultColumn is the metadata about a column of the rows produced by an Iterator.
+type ResultColumn struct {
+	// Table is the name of the relation qualifying the column, empty for computed columns.
+	Table string
+	Name  string
+	Type  DataType
+}
+
+func (c ResultColumn) String() string {
+	if c.Table == "" {
+		return c.Name
+	}
+	return c.Table + "." + c.Name
+}
+
+// Iterator is a Volcano style operator. Rows are pulled one at a time with Next
+// between a call to Open and a call to Close.
+// Next returns io.EOF once all the rows have been produced.
+type Iterator interface {
+	Columns() []ResultColumn
+	Open() error
+	Next() (Row, error)
+	Close() error
+}
+
+// Storage gives access to the rows of the relations of a catalog.
+type Storage interface {
+	// Scan returns an iterator over all the rows of the relation.
+	Scan(r Relation) (Iterator, error)
+}
+
+// Executor compiles plans into trees of iterators.
+type Executor struct {
+	c Catalog
+	s Storage
+}
+
+func NewExecutor(c Catalog, s Storage) *Executor {
+	return &Executor{c: c, s: s}
+}
+
+// Query plans the statement and compiles the plan into an iterator.
+func (e Executor) Query(stmt Stmt) (Iterator, error) {
+	plan, err := NewPlanner(e.c).Plan(stmt)
+	if err != nil {
+		return nil, err
+	}
+	return e.Compile(plan)
+}
+
+// Compile turns a plan into a tree of iterators ready to be opened.
+func (e Executor) Compile(plan PlanNode) (Iterator, error) {
+	switch n := plan.(type) {
+	case *TableScanNode:
+		return e.compileTableScan(n)
+	case *FilterNode:
+		return e.compileFilter(n)
+	case *ProjectionNode:
+		return e.compileProjection(n)
+	case *SortNode:
+		return e.compileSort(n)
+	case *LimitNode:
+		return e.compileLimit(n)
+	case *OffsetNode:
+		return e.compileOffset(n)
+	case *HashDistinctNode:
+		return e.compileDistinct(n.From, false)
+	case *SortedDistinctNode:
+		return e.compileDistinct(n.From, true)
+	case *NestedLoopNode:
+		return e.compileNestedLoop(n)
+	case *HashJoinNode:
+		return e.compileHashJoin(n)
+	case *MergeJoinNode:
+		return e.compileMergeJoin(n)
+	case *HashAggregateNode:
+		return e.compileAggregate(n.Groups, n.Aggregates, n.From, false)
+	case *GroupAggregateNode:
+		return e.compileAggregate(n.Groups, n.Aggregates, n.From, true)
+	case nil:
+		return nil, errors.New("invalid plan: missing node")
+	default:
+		return nil, fmt.Errorf("unsupported plan node %T", plan)
+	}
+}
+
+func (e Executor) compileTableScan(n *TableScanNode) (Iterator, error) {
+	if e.s == nil {
+		return nil, errors.New("no storage configured")
+	}
+	relation, err := e.c.GetRelation(n.relation())
+	if err != nil {
+		return nil, err
+	}
+	it, err := e.s.Scan(relation)
+	if err != nil || n.Alias == "" {
+		return it, err
+	}
+
+	cols := make([]ResultColumn, len(it.Columns()))
+	for i, c := range it.Columns() {
+		c.Table = n.Alias
+		cols[i] = c
+	}
+	return &aliasIterator{Iterator: it, cols: cols}, nil
+}
+
+func (e Executor) compileFilter(n *FilterNode) (Iterator, error) {
+	from, err := e.Compile(n.From)
+	if err != nil {
+		return nil, err
+	}
+	pred, err := compileExpr(n.Filter, from.Columns())
+	if err != nil {
+		return nil, err
+	}
+	return &filterIterator{from: from, pred: pred}, nil
+}
+
+func (e Executor) compileProjection(n *ProjectionNode) (Iterator, error) {
+	from, err := e.Compile(n.From)
+	if err != nil {
+		return nil, err
+	}
+
+	in := from.Columns()
+	var exprs []evalFunc
+	var cols []ResultColumn
+	for _, c := range n.Columns {
+		if n, ok := c.(*QualifiedName); ok && n.Column == "*" {
+			pos, err := starColumns(n, in)
+			if err != nil {
+				return nil, err
+			}
+			for _, i := range pos {
+				exprs = append(exprs, columnValue(i))
+				cols = append(cols, in[i])
+			}
+			continue
+		}
+		fn, col, err := compileColumn(c, in)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, fn)
+		cols = append(cols, col)
+	}
+	return &projectionIterator{from: from, exprs: exprs, cols: cols}, nil
+}
+
+func (e Executor) compileSort(n *SortNode) (Iterator, error) {
+	from, err := e.Compile(n.From)
+	if err != nil {
+		return nil, err
+	}
+
+	it := sortIterator{from: from}
+	for _, k := range n.Keys {
+		fn, err := compileExpr(k, from.Columns())
+		if err != nil {
+			return nil, err
+		}
+		it.keys = append(it.keys, fn)
+	}
+	return &it, nil
+}
+
+func (e Executor) compileLimit(n *LimitNode) (Iterator, error) {
+	from, err := e.Compile(n.From)
+	if err != nil {
+		return nil, err
+	}
+	return &limitIterator{from: from, limit: n.Value}, nil
+}
+
+func (e Executor) compileOffset(n *OffsetNode) (Iterator, error) {
+	from, err := e.Compile(n.From)
+	if err != nil {
+		return nil, err
+	}
+	return &offsetIterator{from: from, offset: n.Value}, nil
+}
+
+// resolveColumn finds the position of a column in a row, the name can be qualified by its table.
+// The schema and catalog qualifying the table are checked by the planner.
+func resolveColumn(cols []ResultColumn, name *QualifiedName) (int, error) {
+	pos := -1
+	for i, c := range cols {
+		if c.Name != name.Column || (name.Table != "" && c.Table != name.Table) {
+			continue
+		}
+		if pos != -1 {
+			return -1, fmt.Errorf("ambiguous column \"%s\"", name)
+		}
+		pos = i
+	}
+	if pos == -1 {
+		return -1, fmt.Errorf("unknown column \"%s\"", name)
+	}
+	return pos, nil
+}
+
+// starColumns finds the positions of the columns a * of the select list stands for in a row,
+// all of them or those of the table qualifying it.
+func starColumns(star *QualifiedName, cols []ResultColumn) ([]int, error) {
+	var pos []int
+	for i, c := range cols {
+		if star.Table == "" || c.Table == star.Table {
+			pos = append(pos, i)
+		}
+	}
+	if pos == nil && star.Table != "" {
+		return nil, fmt.Errorf("unknown table \"%s\"", star.Table)
+	}
+	return pos, nil
+}
+
+// filterIterator only returns the rows matching its predicate.
+type filterIterator struct {
+	from Iterator
+	pred evalFunc
+}
+
+func (it *filterIterator) Columns() []ResultColumn { return it.from.Columns() }
+func (it *filterIterator) Open() error             { return it.from.Open() }
+func (it *filterIterator) Close() error            { return it.from.Close() }
+
+func (it *filterIterator) Next() (Row, error) {
+	for {
+		row, err := it.from.Next()
+		if err != nil {
+			return nil, err
+		}
+		v, err := it.pred(row)
+		if err != nil {
+			return nil, err
+		}
+		if isTrue(v) {
+			return row, nil
+		}
+	}
+}
+
+// projectionIterator computes the columns of its output from the rows of its input.
+type projectionIterator struct {
+	from  Iterator
+	exprs []evalFunc
+	cols  []ResultColumn
+}
+
+func (it *projectionIterator) Columns() []ResultColumn { return it.cols }
+func (it *projectionIterator) Open() error             { return it.from.Open() }
+func (it *projectionIterator) Close() error            { return it.from.Close() }
+
+func (it *projectionIterator) Next() (Row, error) {
+	row, err := it.from.Next()
+	if err != nil {
+		return nil, err
+	}
+	out := make(Row, len(it.exprs))
+	for i, fn := range it.exprs {
+		if out[i], err = fn(row); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// aliasIterator renames the columns of its input.
+type aliasIterator struct {
+	Iterator
+	cols []ResultColumn
+}
+
+func (it *aliasIterator) Columns() []ResultColumn { return it.cols }
+
+// sortIterator materializes its input on Open and returns it sorted by the keys.
+type sortIterator struct {
+	from Iterator
+	keys []evalFunc
+	rows []Row
+	pos  int
+}
+
+func (it *sortIterator) Columns() []ResultColumn { return it.from.Columns() }
+
+func (it *sortIterator) Open() error {
+	if err := it.from.Open(); err != nil {
+		return err
+	}
+	rows, err := drain(it.from)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]Row, len(rows))
+	for i, row := range rows {
+		keys[i] = make(Row, len(it.keys))
+		for j, fn := range it.keys {
+			if keys[i][j], err = fn(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	var cmpErr error
+	sort.Stable(sortRows{rows: rows, keys: keys, less: func(a, b Row) bool {
+		c, err := compareRows(a, b)
+		if err != nil && cmpErr == nil {
+			cmpErr = err
+		}
+		return c < 0
+	}})
+	if cmpErr != nil {
+		return cmpErr
+	}
+	it.rows = rows
+	it.pos = 0
+	return nil
+}
+
+func (it *sortIterator) Next() (Row, error) {
+	if it.pos >= len(it.rows) {
+		return nil, io.EOF
+	}
+	row := it.rows[it.pos]
+	it.pos++
+	return row, nil
+}
+
+func (it *sortIterator) Close() error {
+	it.rows = nil
+	return it.from.Close()
+}
+
+// limitIterator stops after a number of rows.
+type limitIterator struct {
+	from  Iterator
+	limit int
+	count int
+}
+
+func (it *limitIterator) Columns() []ResultColumn { return it.from.Columns() }
+func (it *limitIterator) Close() error            { return it.from.Close() }
+
+func (it *limitIterator) Open() error {
+	it.count = 0
+	return it.from.Open()
+}
+
+func (it *limitIterator) Next() (Row, error) {
+	if it.count >= it.limit {
+		return nil, io.EOF
+	}
+	row, err := it.from.Next()
+	if err != nil {
+		return nil, err
+	}
+	it.count++
+	return row, nil
+}
+
+// offsetIterator discards a number of rows before returning its input.
+type offsetIterator struct {
+	from    Iterator
+	offset  int
+	skipped int
+}
+
+func (it *offsetIterator) Columns() []ResultColumn { return it.from.Columns() }
+func (it *offsetIterator) Close() error            { return it.from.Close() }
+
+func (it *offsetIterator) Open() error {
+	it.skipped = 0
+	return it.from.Open()
+}
+
+func (it *offsetIterator) Next() (Row, error) {
+	for it.skipped < it.offset {
+		if _, err := it.from.Next(); err != nil {
+			return nil, err
+		}
+		it.skipped++
+	}
+	return it.from.Next()
+}
+
+// drain reads all the remaining rows of an opened iterator.
+func drain(it Iterator) ([]Row, error) {
+	var rows []Row
+	for {
+		row, err := it.Next()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+// sortRows sorts rows along with their keys.
+type sortRows struct {
+	rows []Row
+	keys []Row
+	less func(a, b Row) bool
+}
+
+func (s sortRows) Len() int           { return len(s.rows) }
+func (s sortRows) Less(i, j int) bool { return s.less(s.keys[i], s.keys[j]) }
+func (s sortRows) Swap(i, j int) {
+	s.rows[i], s.rows[j] = s.rows[j], s.rows[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+// compareRows compares two rows value by value.
+func compareRows(a, b Row) (int, error) {
+	for k := range a {
+		c, err := compareValues(a[k], b[k])
+		if err != nil {
+			return 0, err
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
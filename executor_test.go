@@ -0,0 +1,842 @@
+package sql_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	sql "github.com/ndilsou/go-rdbms-playground"
+)
+
+func TestExecutor_Query(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		cols    []string
+		want    []sql.Row
+		wantErr bool
+		// unordered compares the rows regardless of their order.
+		unordered bool
+	}{
+		{
+			name:  "select all",
+			query: `SELECT * FROM t1`,
+			cols:  []string{"t1.a", "t1.b", "t1.c"},
+			want: []sql.Row{
+				{int64(1), 1.5, "x"},
+				{int64(2), 0.5, "y"},
+				{int64(3), 2.5, "z"},
+				{int64(4), nil, "x"},
+			},
+		},
+		{
+			name:  "projection",
+			query: `SELECT c, a FROM t1`,
+			cols:  []string{"t1.c", "t1.a"},
+			want: []sql.Row{
+				{"x", int64(1)},
+				{"y", int64(2)},
+				{"z", int64(3)},
+				{"x", int64(4)},
+			},
+		},
+		{
+			name:  "filter",
+			query: `SELECT a FROM t1 WHERE c = 'x' OR a > 2`,
+			cols:  []string{"t1.a"},
+			want:  []sql.Row{{int64(1)}, {int64(3)}, {int64(4)}},
+		},
+		{
+			name:  "escaped strings",
+			query: `SELECT c || '''s' FROM t1 WHERE c = E'\u0079'`,
+			cols:  []string{"c || '''s'"},
+			want:  []sql.Row{{"y's"}},
+		},
+		{
+			name:  "quoted identifiers",
+			query: `SELECT "a" FROM "t1" WHERE "t1"."c" = 'x'`,
+			cols:  []string{"t1.a"},
+			want:  []sql.Row{{int64(1)}, {int64(4)}},
+		},
+		{
+			name:  "schema qualified names",
+			query: `SELECT public.t1.a FROM public.t1 WHERE t1.c = 'x'`,
+			cols:  []string{"t1.a"},
+			want:  []sql.Row{{int64(1)}, {int64(4)}},
+		},
+		{
+			name:  "filter unknown",
+			query: `SELECT a FROM t1 WHERE b < 1 OR c = 'z'`,
+			cols:  []string{"t1.a"},
+			want:  []sql.Row{{int64(2)}, {int64(3)}},
+		},
+		{
+			name:  "sort",
+			query: `SELECT a, b FROM t1 ORDER BY b`,
+			cols:  []string{"t1.a", "t1.b"},
+			want: []sql.Row{
+				{int64(4), nil},
+				{int64(2), 0.5},
+				{int64(1), 1.5},
+				{int64(3), 2.5},
+			},
+		},
+		{
+			name:  "limit and offset",
+			query: `SELECT a FROM t1 ORDER BY a OFFSET 1 LIMIT 2`,
+			cols:  []string{"t1.a"},
+			want:  []sql.Row{{int64(2)}, {int64(3)}},
+		},
+		{
+			name:  "limit past the end",
+			query: `SELECT a FROM t1 LIMIT 10`,
+			cols:  []string{"t1.a"},
+			want:  []sql.Row{{int64(1)}, {int64(2)}, {int64(3)}, {int64(4)}},
+		},
+		{
+			name:      "inner join",
+			unordered: true,
+			query:     `SELECT t1.a, d FROM t1 JOIN t2 ON t1.a = t2.a`,
+			cols:      []string{"t1.a", "t2.d"},
+			want: []sql.Row{
+				{int64(1), "one"},
+				{int64(3), "three"},
+				{int64(3), "trois"},
+			},
+		},
+		{
+			name:      "qualified star in join",
+			unordered: true,
+			query:     `SELECT t2.* FROM t1 JOIN t2 ON t1.a = t2.a`,
+			cols:      []string{"t2.a", "t2.d"},
+			want: []sql.Row{
+				{int64(1), "one"},
+				{int64(3), "three"},
+				{int64(3), "trois"},
+			},
+		},
+		{
+			name:      "left join",
+			unordered: true,
+			query:     `SELECT t1.a, d FROM t1 LEFT JOIN t2 ON t1.a = t2.a`,
+			cols:      []string{"t1.a", "t2.d"},
+			want: []sql.Row{
+				{int64(1), "one"},
+				{int64(2), nil},
+				{int64(3), "three"},
+				{int64(3), "trois"},
+				{int64(4), nil},
+			},
+		},
+		{
+			name:      "right join",
+			unordered: true,
+			query:     `SELECT t1.a, d FROM t1 RIGHT JOIN t2 ON t1.a = t2.a`,
+			cols:      []string{"t1.a", "t2.d"},
+			want: []sql.Row{
+				{int64(1), "one"},
+				{int64(3), "three"},
+				{int64(3), "trois"},
+				{nil, "five"},
+				{nil, "none"},
+			},
+		},
+		{
+			name:      "full join",
+			unordered: true,
+			query:     `SELECT t1.a, t2.a FROM t1 FULL OUTER JOIN t2 ON t1.a = t2.a WHERE t1.a = 2 OR t2.a = 5`,
+			cols:      []string{"t1.a", "t2.a"},
+			want: []sql.Row{
+				{int64(2), nil},
+				{nil, int64(5)},
+			},
+		},
+		{
+			name:  "non equi join",
+			query: `SELECT t1.a, t2.a FROM t1 JOIN t2 ON t1.a > t2.a WHERE t1.a = 4`,
+			cols:  []string{"t1.a", "t2.a"},
+			want: []sql.Row{
+				{int64(4), int64(1)},
+				{int64(4), int64(3)},
+				{int64(4), int64(3)},
+			},
+		},
+		{
+			name:      "join on reversed criterion",
+			query:     `SELECT t1.c, t2.d FROM t1 JOIN t2 ON t2.a = t1.a`,
+			cols:      []string{"t1.c", "t2.d"},
+			unordered: true,
+			want: []sql.Row{
+				{"x", "one"},
+				{"z", "three"},
+				{"z", "trois"},
+			},
+		},
+		{
+			name:      "left join on larger outer input",
+			query:     `SELECT t2.d, t1.c FROM t2 LEFT JOIN t1 ON t2.a = t1.a`,
+			cols:      []string{"t2.d", "t1.c"},
+			unordered: true,
+			want: []sql.Row{
+				{"one", "x"},
+				{"three", "z"},
+				{"trois", "z"},
+				{"five", nil},
+				{"none", nil},
+			},
+		},
+		{
+			name:      "right join on larger outer input",
+			query:     `SELECT t2.d, t1.c FROM t2 RIGHT JOIN t1 ON t2.a = t1.a`,
+			cols:      []string{"t2.d", "t1.c"},
+			unordered: true,
+			want: []sql.Row{
+				{"one", "x"},
+				{"three", "z"},
+				{"trois", "z"},
+				{nil, "y"},
+				{nil, "x"},
+			},
+		},
+		{
+			name:  "merge inner join",
+			query: `SELECT t1.a, e FROM t1 JOIN t3 ON t1.a = t3.a`,
+			cols:  []string{"t1.a", "t3.e"},
+			want: []sql.Row{
+				{int64(1), "p"},
+				{int64(1), "q"},
+				{int64(2), "r"},
+			},
+		},
+		{
+			name:  "merge left join",
+			query: `SELECT t1.a, e FROM t1 LEFT JOIN t3 ON t1.a = t3.a`,
+			cols:  []string{"t1.a", "t3.e"},
+			want: []sql.Row{
+				{int64(1), "p"},
+				{int64(1), "q"},
+				{int64(2), "r"},
+				{int64(3), nil},
+				{int64(4), nil},
+			},
+		},
+		{
+			name:  "merge right join",
+			query: `SELECT t1.a, e FROM t1 RIGHT JOIN t3 ON t3.a = t1.a`,
+			cols:  []string{"t1.a", "t3.e"},
+			want: []sql.Row{
+				{nil, "n"},
+				{int64(1), "p"},
+				{int64(1), "q"},
+				{int64(2), "r"},
+				{nil, "s"},
+			},
+		},
+		{
+			name:  "merge full join",
+			query: `SELECT t1.a, e FROM t1 FULL JOIN t3 ON t1.a = t3.a`,
+			cols:  []string{"t1.a", "t3.e"},
+			want: []sql.Row{
+				{nil, "n"},
+				{int64(1), "p"},
+				{int64(1), "q"},
+				{int64(2), "r"},
+				{int64(3), nil},
+				{int64(4), nil},
+				{nil, "s"},
+			},
+		},
+		{
+			name:  "group by",
+			query: `SELECT c, COUNT(*), COUNT(b), SUM(a), AVG(b), MIN(b), MAX(a) FROM t1 GROUP BY c`,
+			cols:  []string{"t1.c", "COUNT(*)", "COUNT(b)", "SUM(a)", "AVG(b)", "MIN(b)", "MAX(a)"},
+			want: []sql.Row{
+				{"x", int64(2), int64(1), int64(5), 1.5, 1.5, int64(4)},
+				{"y", int64(1), int64(1), int64(2), 0.5, 0.5, int64(2)},
+				{"z", int64(1), int64(1), int64(3), 2.5, 2.5, int64(3)},
+			},
+		},
+		{
+			name:  "group by on sorted relation",
+			query: `SELECT a, COUNT(*), MAX(e) FROM t3 GROUP BY a`,
+			cols:  []string{"t3.a", "COUNT(*)", "MAX(e)"},
+			want: []sql.Row{
+				{nil, int64(1), "n"},
+				{int64(1), int64(2), "q"},
+				{int64(2), int64(1), "r"},
+				{int64(6), int64(1), "s"},
+			},
+		},
+		{
+			name:  "having",
+			query: `SELECT c, COUNT(*) FROM t1 GROUP BY c HAVING COUNT(*) > 1`,
+			cols:  []string{"t1.c", "COUNT(*)"},
+			want:  []sql.Row{{"x", int64(2)}},
+		},
+		{
+			name:  "having on aggregate not selected",
+			query: `SELECT c FROM t1 GROUP BY c HAVING SUM(a) > 2 AND c <> 'z'`,
+			cols:  []string{"t1.c"},
+			want:  []sql.Row{{"x"}},
+		},
+		{
+			name:  "having without group by",
+			query: `SELECT COUNT(*) FROM t1 HAVING COUNT(*) > 1`,
+			cols:  []string{"COUNT(*)"},
+			want:  []sql.Row{{int64(4)}},
+		},
+		{
+			name:  "having without group by filters the single group",
+			query: `SELECT COUNT(*) FROM t1 HAVING COUNT(*) > 10`,
+			cols:  []string{"COUNT(*)"},
+		},
+		{
+			name:  "distinct",
+			query: `SELECT DISTINCT c FROM t1`,
+			cols:  []string{"t1.c"},
+			want:  []sql.Row{{"x"}, {"y"}, {"z"}},
+		},
+		{
+			name:  "distinct with nulls",
+			query: `SELECT DISTINCT a FROM t2`,
+			cols:  []string{"t2.a"},
+			want:  []sql.Row{{int64(1)}, {int64(3)}, {int64(5)}, {nil}},
+		},
+		{
+			name:  "distinct on sorted rows",
+			query: `SELECT DISTINCT t1.a, c FROM t1 JOIN t3 ON t1.a = t3.a ORDER BY c, t1.a`,
+			cols:  []string{"t1.a", "t1.c"},
+			want:  []sql.Row{{int64(1), "x"}, {int64(2), "y"}},
+		},
+		{
+			name:  "distinct with limit",
+			query: `SELECT DISTINCT c FROM t1 ORDER BY c LIMIT 2`,
+			cols:  []string{"t1.c"},
+			want:  []sql.Row{{"x"}, {"y"}},
+		},
+		{
+			name:  "column aliases",
+			query: `SELECT a AS k, c label FROM t1 ORDER BY label, k`,
+			cols:  []string{"k", "label"},
+			want:  []sql.Row{{int64(1), "x"}, {int64(4), "x"}, {int64(2), "y"}, {int64(3), "z"}},
+		},
+		{
+			name:  "sort on aliased column",
+			query: `SELECT a AS k, c FROM t1 ORDER BY c, a`,
+			cols:  []string{"k", "t1.c"},
+			want:  []sql.Row{{int64(1), "x"}, {int64(4), "x"}, {int64(2), "y"}, {int64(3), "z"}},
+		},
+		{
+			name:  "sort on alias and column not selected",
+			query: `SELECT a AS k FROM t1 ORDER BY c, k`,
+			cols:  []string{"k"},
+			want:  []sql.Row{{int64(1)}, {int64(4)}, {int64(2)}, {int64(3)}},
+		},
+		{
+			name:  "aggregate alias",
+			query: `SELECT c, COUNT(*) AS n FROM t1 GROUP BY c ORDER BY n, c`,
+			cols:  []string{"t1.c", "n"},
+			want:  []sql.Row{{"y", int64(1)}, {"z", int64(1)}, {"x", int64(2)}},
+		},
+		{
+			name:  "self join with alias",
+			query: `SELECT t1.a, other.a FROM t1 JOIN t1 other ON t1.c = other.c WHERE t1.a < other.a`,
+			cols:  []string{"t1.a", "other.a"},
+			want:  []sql.Row{{int64(1), int64(4)}},
+		},
+		{
+			name:  "join on keys of different types",
+			query: `SELECT id, name FROM events JOIN holidays ON at = day ORDER BY id`,
+			cols:  []string{"events.id", "holidays.name"},
+			want:  []sql.Row{{int64(1), "mid"}, {int64(3), "leap"}},
+		},
+		{
+			name:    "relation hidden by its alias",
+			query:   `SELECT t1.a FROM t1 AS x`,
+			wantErr: true,
+		},
+		{
+			name:  "predicate precedence",
+			query: `SELECT a FROM t1 WHERE a = 1 OR a = 2 AND c = 'x' OR (a = 3 OR a = 4) AND c = 'x'`,
+			cols:  []string{"t1.a"},
+			want:  []sql.Row{{int64(1)}, {int64(4)}},
+		},
+		{
+			name:  "expression column",
+			query: `SELECT a, (a = 1 OR a = 4) AND c = 'x' FROM t1 WHERE a < 3`,
+			cols:  []string{"t1.a", "(a = 1 OR a = 4) AND c = 'x'"},
+			want:  []sql.Row{{int64(1), true}, {int64(2), false}},
+		},
+		{
+			name:  "join on conjunction",
+			query: `SELECT t1.a, d FROM t1 JOIN t2 ON t1.a = t2.a AND (d = 'trois' OR t1.a = 1)`,
+			cols:  []string{"t1.a", "t2.d"},
+			want:  []sql.Row{{int64(1), "one"}, {int64(3), "trois"}},
+		},
+		{
+			name:  "computed columns",
+			query: `SELECT a * 2 + 1 AS k, c || '!', -b FROM t1 WHERE a % 2 = 0`,
+			cols:  []string{"k", "c || '!'", "-b"},
+			want:  []sql.Row{{int64(5), "y!", -0.5}, {int64(9), "x!", nil}},
+		},
+		{
+			name:  "aggregate of expression",
+			query: `SELECT c, SUM(a * b) / 2, MAX(a) - MIN(a) FROM t1 GROUP BY c`,
+			cols:  []string{"t1.c", "SUM(a * b) / 2", "MAX(a) - MIN(a)"},
+			want: []sql.Row{
+				{"x", 0.75, int64(3)},
+				{"y", 0.5, int64(0)},
+				{"z", 3.75, int64(0)},
+			},
+		},
+		{
+			name:    "division by zero",
+			query:   `SELECT a / (a - 1) FROM t1`,
+			wantErr: true,
+		},
+		{
+			name:  "is null",
+			query: `SELECT a FROM t1 WHERE b IS NULL`,
+			cols:  []string{"t1.a"},
+			want:  []sql.Row{{int64(4)}},
+		},
+		{
+			name:  "not",
+			query: `SELECT a, b IS NOT NULL FROM t1 WHERE NOT b > 1 OR NOT (b IS NOT NULL OR c = 'z')`,
+			cols:  []string{"t1.a", "b IS NOT NULL"},
+			want:  []sql.Row{{int64(2), true}, {int64(4), false}},
+		},
+		{
+			name:      "outer join rows without match",
+			query:     `SELECT t2.a, d FROM t2 LEFT JOIN t1 ON t1.a = t2.a WHERE t1.a IS NULL`,
+			cols:      []string{"t2.a", "t2.d"},
+			want:      []sql.Row{{int64(5), "five"}, {nil, "none"}},
+			unordered: true,
+		},
+		{
+			name:  "in",
+			query: `SELECT a FROM t1 WHERE a IN (2, 4, 6) OR c NOT IN ('x', 'y')`,
+			cols:  []string{"t1.a"},
+			want:  []sql.Row{{int64(2)}, {int64(3)}, {int64(4)}},
+		},
+		{
+			name:  "not in with null",
+			query: `SELECT a FROM t1 WHERE a NOT IN (1, NULL)`,
+			cols:  []string{"t1.a"},
+		},
+		{
+			name:  "between",
+			query: `SELECT a FROM t1 WHERE b BETWEEN 0.5 AND 2 AND a NOT BETWEEN 2 AND 3`,
+			cols:  []string{"t1.a"},
+			want:  []sql.Row{{int64(1)}},
+		},
+		{
+			name:  "predicate columns",
+			query: `SELECT a NOT IN (1, 2), b BETWEEN 1 AND 2, c LIKE 'x' FROM t1 WHERE a > 2`,
+			cols:  []string{"a NOT IN (1, 2)", "b BETWEEN 1 AND 2", "c LIKE 'x'"},
+			want:  []sql.Row{{true, false, false}, {true, nil, true}},
+		},
+		{
+			name:  "like",
+			query: `SELECT d FROM t2 WHERE d LIKE 't%' OR d LIKE '_n_'`,
+			cols:  []string{"t2.d"},
+			want:  []sql.Row{{"one"}, {"three"}, {"trois"}},
+		},
+		{
+			name:  "case",
+			query: `SELECT a, CASE WHEN b < 1 THEN 'low' WHEN b < 2 THEN 'mid' ELSE 'high' END AS bucket FROM t1`,
+			cols:  []string{"t1.a", "bucket"},
+			want: []sql.Row{
+				{int64(1), "mid"},
+				{int64(2), "low"},
+				{int64(3), "high"},
+				{int64(4), "high"},
+			},
+		},
+		{
+			name:  "simple case on values of different types",
+			query: `SELECT name, CASE day WHEN TIMESTAMP '2024-02-29T08:00:00Z' THEN 'yes' ELSE 'no' END AS leap, CASE id WHEN 1.0 THEN 'one' END FROM holidays JOIN events ON day = at`,
+			cols:  []string{"holidays.name", "leap", "CASE id WHEN 1.0 THEN 'one' END"},
+			want:  []sql.Row{{"mid", "no", "one"}, {"leap", "yes", nil}},
+		},
+		{
+			name:  "case in where and order by",
+			query: `SELECT a, c FROM t1 WHERE CASE c WHEN 'y' THEN 0 ELSE 1 END = 1 ORDER BY CASE c WHEN 'z' THEN 0 ELSE 1 END, a`,
+			cols:  []string{"t1.a", "t1.c"},
+			want:  []sql.Row{{int64(3), "z"}, {int64(1), "x"}, {int64(4), "x"}},
+		},
+		{
+			name:  "case in aggregate",
+			query: `SELECT SUM(CASE WHEN c = 'x' THEN 1 ELSE 0 END), COUNT(CASE WHEN a > 1 THEN a END) FROM t1`,
+			cols:  []string{"SUM(CASE WHEN c = 'x' THEN 1 ELSE 0 END)", "COUNT(CASE WHEN a > 1 THEN a END)"},
+			want:  []sql.Row{{int64(2), int64(3)}},
+		},
+		{
+			name:    "case with incompatible results",
+			query:   `SELECT CASE WHEN a > 1 THEN a ELSE c END FROM t1`,
+			wantErr: true,
+		},
+		{
+			name:  "function calls",
+			query: `SELECT a, ROUND(b * 3, 1) AS r FROM t1 WHERE ABS(a - 3) <= 1 ORDER BY abs(a - 3), a`,
+			cols:  []string{"t1.a", "r"},
+			want:  []sql.Row{{int64(3), 7.5}, {int64(2), 1.5}, {int64(4), nil}},
+		},
+		{
+			name:  "function of aggregate",
+			query: `SELECT c, ROUND(AVG(b)) FROM t1 GROUP BY c ORDER BY c`,
+			cols:  []string{"t1.c", "ROUND(AVG(b))"},
+			want:  []sql.Row{{"x", 2.0}, {"y", 1.0}, {"z", 3.0}},
+		},
+		{
+			name:  "string functions",
+			query: `SELECT UPPER(c) AS u, LENGTH(c || c) FROM t1 WHERE LOWER(UPPER(c)) IN ('x', 'z') ORDER BY CONCAT(u, 'x')`,
+			cols:  []string{"u", "LENGTH(c || c)"},
+			want:  []sql.Row{{"X", int64(2)}, {"X", int64(2)}, {"Z", int64(2)}},
+		},
+		{
+			name:  "datetime functions",
+			query: `SELECT id, EXTRACT(month FROM at), STRFTIME('%Y/%m/%d', DATE_TRUNC('week', at)) FROM events WHERE at < TIMESTAMP '2024-02-01T00:00:00Z' ORDER BY id`,
+			cols:  []string{"events.id", "EXTRACT('month', at)", "STRFTIME('%Y/%m/%d', DATE_TRUNC('week', at))"},
+			want:  []sql.Row{{int64(1), int64(1), "2024/01/15"}, {int64(2), int64(1), "2024/01/29"}},
+		},
+		{
+			name:  "datetime addition",
+			query: `SELECT id, DATE_ADD(at, 1, 'month') AS next FROM events WHERE at >= DATE '2024-01-31' ORDER BY next`,
+			cols:  []string{"events.id", "next"},
+			want: []sql.Row{
+				{int64(2), time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC)},
+				{int64(3), time.Date(2024, 3, 29, 8, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:  "datetime aggregates",
+			query: `SELECT MIN(at), MAX(at), COUNT(at) FROM events`,
+			cols:  []string{"MIN(at)", "MAX(at)", "COUNT(at)"},
+			want:  []sql.Row{{time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), time.Date(2024, 2, 29, 8, 0, 0, 0, time.UTC), int64(3)}},
+		},
+		{
+			name:  "casts",
+			query: `SELECT id, CAST(id AS TEXT) || ':' || STRFTIME('%d', at) FROM events WHERE at >= '2024-01-31' AND at::TEXT LIKE '2024-02%' OR id = '4'::INT`,
+			cols:  []string{"events.id", "CAST(id AS TEXT) || ':' || STRFTIME('%d', at)"},
+			want:  []sql.Row{{int64(3), "3:29"}, {int64(4), nil}},
+		},
+		{
+			name:  "casts to DATE",
+			query: `SELECT CAST('2024-01-31' AS DATE), at::date FROM events WHERE id = 1`,
+			cols:  []string{"CAST('2024-01-31' AS DATETIME)", "CAST(at AS DATETIME)"},
+			want:  []sql.Row{{time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)}},
+		},
+		{
+			name:    "comparison of incompatible types",
+			query:   `SELECT a FROM t1 WHERE c > 1`,
+			wantErr: true,
+		},
+		{
+			name:  "aggregate of empty input",
+			query: `SELECT COUNT(*), SUM(a), AVG(b) FROM t1 WHERE a > 10`,
+			cols:  []string{"COUNT(*)", "SUM(a)", "AVG(b)"},
+			want:  []sql.Row{{int64(0), nil, nil}},
+		},
+		{
+			name:    "ambiguous column",
+			query:   `SELECT a FROM t1 JOIN t2 ON t1.a = t2.a`,
+			wantErr: true,
+		},
+		{
+			name:    "sort on unknown column",
+			query:   `SELECT a FROM t1 ORDER BY z`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := sql.NewParser(strings.NewReader(tt.query)).Parse()
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			e := sql.NewExecutor(&mockCatalog{}, &mockStorage{})
+			cols, rows, err := query(e, stmt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Query() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(cols, tt.cols) {
+				t.Errorf("Query() columns = %v, want %v", cols, tt.cols)
+			}
+			if tt.unordered {
+				sortRows(rows)
+				sortRows(tt.want)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("Query() rows = %v, want %v", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutor_Compile(t *testing.T) {
+	e := sql.NewExecutor(&mockCatalog{}, &mockStorage{})
+	if _, err := e.Compile(&sql.TableScanNode{RelationName: "unknown"}); err == nil {
+		t.Errorf("Compile() expected error for unknown relation")
+	}
+	if _, err := e.Compile(&sql.FilterNode{Filter: &sql.QualifiedName{Column: "a"}}); err == nil {
+		t.Errorf("Compile() expected error for missing node")
+	}
+}
+
+func TestExecutor_Columns(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []sql.DataType
+	}{
+		{query: `SELECT a, b, c FROM t1`, want: []sql.DataType{sql.INTEGER, sql.REAL, sql.TEXT}},
+		{query: `SELECT a + 1, a * b, -a, a / 2 FROM t1`, want: []sql.DataType{sql.INTEGER, sql.REAL, sql.INTEGER, sql.INTEGER}},
+		{query: `SELECT c || 'x', a > 1, SUM(a) + 0.5 FROM t1 GROUP BY c, a`, want: []sql.DataType{sql.TEXT, sql.BOOLEAN, sql.REAL}},
+		{query: `SELECT ABS(a), ABS(b), ROUND(a) FROM t1`, want: []sql.DataType{sql.INTEGER, sql.REAL, sql.REAL}},
+		{query: `SELECT UPPER(c), LENGTH(c), SPLIT_PART(c, ',', 1) FROM t1`, want: []sql.DataType{sql.TEXT, sql.INTEGER, sql.TEXT}},
+		{query: `SELECT at, NOW(), EXTRACT(year FROM at), DATE '2024-01-01' FROM events`, want: []sql.DataType{sql.DATETIME, sql.DATETIME, sql.INTEGER, sql.DATETIME}},
+		{query: `SELECT CAST(a AS TEXT), b::INT, CAST(NULL AS DATETIME) FROM t1`, want: []sql.DataType{sql.TEXT, sql.INTEGER, sql.DATETIME}},
+		{query: `SELECT CASE WHEN a > 1 THEN a ELSE b END, CASE a WHEN 1 THEN NULL ELSE c END FROM t1`, want: []sql.DataType{sql.REAL, sql.TEXT}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			stmt, err := sql.NewParser(strings.NewReader(tt.query)).Parse()
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			e := sql.NewExecutor(&mockCatalog{}, &mockStorage{})
+			it, err := e.Query(stmt)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+
+			var got []sql.DataType
+			for _, c := range it.Columns() {
+				got = append(got, c.Type)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Columns() types = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutor_MergeJoin(t *testing.T) {
+	join := func(kind sql.JoinKind, outer, inner sql.PlanNode) *sql.MergeJoinNode {
+		return &sql.MergeJoinNode{
+			Kind:      kind,
+			Criterion: &sql.BinaryExpr{LHS: &sql.QualifiedName{Table: "t2", Column: "a"}, Op: sql.EQ, RHS: &sql.QualifiedName{Table: "t1", Column: "a"}},
+			OuterKey:  &sql.QualifiedName{Table: "t2", Column: "a"},
+			InnerKey:  &sql.QualifiedName{Table: "t1", Column: "a"},
+			Outer:     outer,
+			Inner:     inner,
+		}
+	}
+	sorted := func(key, relation string) sql.PlanNode {
+		return &sql.SortNode{Keys: []sql.Expr{&sql.QualifiedName{Table: relation, Column: key}}, From: &sql.TableScanNode{RelationName: relation}}
+	}
+
+	tests := []struct {
+		name    string
+		plan    sql.PlanNode
+		want    []sql.Row
+		wantErr bool
+	}{
+		{
+			name: "duplicate outer keys",
+			plan: join(sql.FullOuterJoin, sorted("a", "t2"), sorted("a", "t1")),
+			want: []sql.Row{
+				{nil, "none", nil, nil, nil},
+				{int64(1), "one", int64(1), 1.5, "x"},
+				{nil, nil, int64(2), 0.5, "y"},
+				{int64(3), "three", int64(3), 2.5, "z"},
+				{int64(3), "trois", int64(3), 2.5, "z"},
+				{nil, nil, int64(4), nil, "x"},
+				{int64(5), "five", nil, nil, nil},
+			},
+		},
+		{
+			name:    "unsorted input",
+			plan:    join(sql.InnerJoin, &sql.TableScanNode{RelationName: "t2"}, sorted("a", "t1")),
+			wantErr: true,
+		},
+		{
+			name: "keys of different types",
+			plan: &sql.MergeJoinNode{
+				Kind:      sql.InnerJoin,
+				Criterion: &sql.BinaryExpr{LHS: &sql.QualifiedName{Table: "events", Column: "at"}, Op: sql.EQ, RHS: &sql.QualifiedName{Table: "holidays", Column: "day"}},
+				OuterKey:  &sql.QualifiedName{Table: "events", Column: "at"},
+				InnerKey:  &sql.QualifiedName{Table: "holidays", Column: "day"},
+				Outer:     sorted("at", "events"),
+				Inner:     sorted("day", "holidays"),
+			},
+			want: []sql.Row{
+				{int64(1), time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), "2024-01-15T10:30:00+00:00", "mid"},
+				{int64(3), time.Date(2024, 2, 29, 8, 0, 0, 0, time.UTC), "2024-02-29T08:00:00Z", "leap"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := sql.NewExecutor(&mockCatalog{}, &mockStorage{})
+			it, err := e.Compile(tt.plan)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if err := it.Open(); err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer it.Close()
+
+			var rows []sql.Row
+			for {
+				row, err := it.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					if !tt.wantErr {
+						t.Errorf("Next() error = %v", err)
+					}
+					return
+				}
+				rows = append(rows, row)
+			}
+			if tt.wantErr {
+				t.Errorf("Next() expected error")
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("Next() rows = %v, want %v", rows, tt.want)
+			}
+		})
+	}
+}
+
+// query runs a statement and collects the names of the columns and the rows returned.
+func query(e *sql.Executor, stmt sql.Stmt) ([]string, []sql.Row, error) {
+	it, err := e.Query(stmt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := it.Open(); err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+
+	var cols []string
+	for _, c := range it.Columns() {
+		cols = append(cols, c.String())
+	}
+	var rows []sql.Row
+	for {
+		row, err := it.Next()
+		if err == io.EOF {
+			return cols, rows, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+// sortRows orders rows by their text representation.
+func sortRows(rows []sql.Row) {
+	sort.Slice(rows, func(i, j int) bool {
+		return fmt.Sprint(rows[i]) < fmt.Sprint(rows[j])
+	})
+}
+
+// testTables holds the rows of testRelations, in the order of their columns.
+var testTables = map[string]struct {
+	rows []sql.Row
+}{
+	"t1": {
+		rows: []sql.Row{
+			{int64(1), 1.5, "x"},
+			{int64(2), 0.5, "y"},
+			{int64(3), 2.5, "z"},
+			{int64(4), nil, "x"},
+		},
+	},
+	"t2": {
+		rows: []sql.Row{
+			{int64(1), "one"},
+			{int64(3), "three"},
+			{int64(3), "trois"},
+			{int64(5), "five"},
+			{nil, "none"},
+		},
+	},
+	"t3": {
+		rows: []sql.Row{
+			{nil, "n"},
+			{int64(1), "p"},
+			{int64(1), "q"},
+			{int64(2), "r"},
+			{int64(6), "s"},
+		},
+	},
+	"events": {
+		rows: []sql.Row{
+			{int64(1), time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+			{int64(2), time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)},
+			{int64(3), time.Date(2024, 2, 29, 8, 0, 0, 0, time.UTC)},
+			{int64(4), nil},
+		},
+	},
+	"holidays": {
+		rows: []sql.Row{
+			{"2024-02-29T08:00:00Z", "leap"},
+			{"2024-01-15T10:30:00+00:00", "mid"},
+			{nil, "none"},
+		},
+	},
+}
+
+type mockStorage struct {
+}
+
+func (m *mockStorage) Scan(r sql.Relation) (sql.Iterator, error) {
+	tbl, ok := testTables[r.Name]
+	if !ok {
+		return nil, errors.New("no table")
+	}
+	var cols []sql.ResultColumn
+	for _, c := range r.Columns {
+		cols = append(cols, sql.ResultColumn{Table: r.Name, Name: c.Name, Type: c.Type})
+	}
+	return &mockIterator{cols: cols, rows: tbl.rows}, nil
+}
+
+type mockIterator struct {
+	cols []sql.ResultColumn
+	rows []sql.Row
+	pos  int
+}
+
+func (m *mockIterator) Columns() []sql.ResultColumn { return m.cols }
+func (m *mockIterator) Close() error                { return nil }
+
+func (m *mockIterator) Open() error {
+	m.pos = 0
+	return nil
+}
+
+func (m *mockIterator) Next() (sql.Row, error) {
+	if m.pos >= len(m.rows) {
+		return nil, io.EOF
+	}
+	row := m.rows[m.pos]
+	m.pos++
+	return row, nil
+}
@@ -0,0 +1,11 @@
+package sql
+
+import "strings"
+
+// UnregisterFunction removes all the overloads registered under the name,
+// so that the tests registering functions leave the registry as they found it.
+func UnregisterFunction(name string) {
+	functions.Lock()
+	defer functions.Unlock()
+	delete(functions.m, strings.ToUpper(name))
+}
@@ -0,0 +1,229 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+// Function is a scalar function which can be called from the expressions of a statement.
+type Function struct {
+	Name string
+	// Args are the types of the parameters, a NULL parameter accepts values of any type
+	// and a REAL parameter also accepts INTEGER values, which are converted before the call.
+	Args []DataType
+	// Variadic makes the last parameter accept any number of values, including none.
+	Variadic bool
+	// Returns is the type of the result.
+	Returns DataType
+	// CalledOnNull makes Call receive the NULL arguments,
+	// otherwise the result is NULL without calling it when any argument is NULL.
+	CalledOnNull bool
+	Call         func(args []Value) (Value, error)
+}
+
+// String describes the signature of the function.
+func (f *Function) String() string {
+	args := make([]string, len(f.Args))
+	for i, t := range f.Args {
+		args[i] = t.String()
+	}
+	if f.Variadic && len(args) > 0 {
+		args[len(args)-1] += "..."
+	}
+	return fmt.Sprintf("%s(%s) %s", f.Name, strings.Join(args, ", "), f.Returns)
+}
+
+// accepts checks if the function can be called with arguments of the given types.
+// A NULL type stands for a type which is not known in advance and is always accepted.
+func (f *Function) accepts(types []DataType) bool {
+	n := len(f.Args)
+	if len(types) != n && !(f.Variadic && len(types) >= n-1) {
+		return false
+	}
+	for i, t := range types {
+		if !acceptsType(f.param(i), t) {
+			return false
+		}
+	}
+	return true
+}
+
+// param is the type of the parameter receiving the i-th argument.
+func (f *Function) param(i int) DataType {
+	if i >= len(f.Args) {
+		return f.Args[len(f.Args)-1]
+	}
+	return f.Args[i]
+}
+
+func acceptsType(param, t DataType) bool {
+	return param == NULL || t == NULL || t == param || (param == REAL && t == INTEGER)
+}
+
+var functions = struct {
+	sync.RWMutex
+	m map[string][]*Function
+}{m: make(map[string][]*Function)}
+
+// RegisterFunction makes a function callable from the statements.
+// Functions can be overloaded: a name can be registered again with other parameter types,
+// the first registered function accepting the types of the arguments is called.
+func RegisterFunction(f Function) error {
+	if f.Name == "" || f.Call == nil {
+		return errors.New("invalid function: missing name or implementation")
+	}
+	if f.Variadic && len(f.Args) == 0 {
+		return fmt.Errorf("invalid function %s: variadic without parameters", f.Name)
+	}
+	f.Name = strings.ToUpper(f.Name)
+	if _, ok := lookupAggregateFunc(f.Name); ok {
+		return fmt.Errorf("function %s conflicts with the aggregate function of the same name", f.Name)
+	}
+
+	functions.Lock()
+	defer functions.Unlock()
+	for _, g := range functions.m[f.Name] {
+		if g.Variadic == f.Variadic && sameTypes(g.Args, f.Args) {
+			return fmt.Errorf("function %s is already registered", g)
+		}
+	}
+	functions.m[f.Name] = append(functions.m[f.Name], &f)
+	return nil
+}
+
+func sameTypes(a, b []DataType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupFunction finds the function called by name with arguments of the given types.
+func lookupFunction(name string, types []DataType) (*Function, error) {
+	functions.RLock()
+	defer functions.RUnlock()
+	overloads, ok := functions.m[strings.ToUpper(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown function \"%s\"", name)
+	}
+	for _, f := range overloads {
+		if f.accepts(types) {
+			return f, nil
+		}
+	}
+
+	args := make([]string, len(types))
+	for i, t := range types {
+		args[i] = t.String()
+	}
+	return nil, fmt.Errorf("function %s(%s) does not exist", strings.ToUpper(name), strings.Join(args, ", "))
+}
+
+// callType is the type of the result of a function call.
+func callType(e *CallExpr, cols []ResultColumn) (DataType, *Function, error) {
+	types := make([]DataType, len(e.Args))
+	for i, arg := range e.Args {
+		types[i] = exprType(arg, cols)
+	}
+	f, err := lookupFunction(e.Name, types)
+	if err != nil {
+		return NULL, nil, err
+	}
+	return f.Returns, f, nil
+}
+
+func compileCallExpr(e *CallExpr, cols []ResultColumn) (evalFunc, error) {
+	_, f, err := callType(e, cols)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]evalFunc, len(e.Args))
+	for i, arg := range e.Args {
+		if args[i], err = compileExpr(arg, cols); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(row Row) (Value, error) {
+		values := make([]Value, len(args))
+		for i, fn := range args {
+			v, err := fn(row)
+			if err != nil {
+				return nil, err
+			}
+			if v == nil && !f.CalledOnNull {
+				return nil, nil
+			}
+			if !acceptsType(f.param(i), valueType(v)) {
+				return nil, fmt.Errorf("%s expects %s arguments, got %v", f.Name, f.param(i), v)
+			}
+			if x, ok := v.(int64); ok && f.param(i) == REAL {
+				v = float64(x)
+			}
+			values[i] = v
+		}
+		return f.Call(values)
+	}, nil
+}
+
+// builtinFunctions are the functions registered by default.
+var builtinFunctions = []Function{
+	{Name: "ABS", Args: []DataType{INTEGER}, Returns: INTEGER, Call: func(args []Value) (Value, error) {
+		x := args[0].(int64)
+		if x == math.MinInt64 {
+			return nil, fmt.Errorf("INTEGER out of range: ABS(%d)", x)
+		}
+		if x < 0 {
+			return -x, nil
+		}
+		return x, nil
+	}},
+	{Name: "ABS", Args: []DataType{REAL}, Returns: REAL, Call: func(args []Value) (Value, error) {
+		return math.Abs(args[0].(float64)), nil
+	}},
+	{Name: "ROUND", Args: []DataType{REAL}, Returns: REAL, Call: func(args []Value) (Value, error) {
+		return math.Round(args[0].(float64)), nil
+	}},
+	{Name: "ROUND", Args: []DataType{REAL, INTEGER}, Returns: REAL, Call: func(args []Value) (Value, error) {
+		return roundScale(args[0].(float64), args[1].(int64)), nil
+	}},
+}
+
+// roundScale rounds x to n decimal places, to a multiple of a power of ten when n is negative.
+// x is returned unchanged when the scale is past the precision of a REAL, and 0 when the scale underflows.
+func roundScale(x float64, n int64) float64 {
+	if n > 308 {
+		return x
+	}
+	if n < -308 {
+		return 0
+	}
+	scale := math.Pow10(int(n))
+	y := x * scale
+	if math.IsInf(y, 0) || math.Abs(y) >= 1<<52 {
+		// y has no fractional digits left to round.
+		return x
+	}
+	return math.Round(y) / scale
+}
+
+func init() {
+	registerBuiltins(builtinFunctions)
+}
+
+// registerBuiltins registers functions which are known to be valid.
+func registerBuiltins(fns []Function) {
+	for _, f := range fns {
+		if err := RegisterFunction(f); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package sql_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	sql "github.com/ndilsou/go-rdbms-playground"
+)
+
+func TestRegisterFunction(t *testing.T) {
+	t.Cleanup(func() { sql.UnregisterFunction("test_register") })
+	call := func(args []sql.Value) (sql.Value, error) { return nil, nil }
+	tests := []struct {
+		name    string
+		fn      sql.Function
+		wantErr bool
+	}{
+		{name: "new function", fn: sql.Function{Name: "test_register", Args: []sql.DataType{sql.TEXT}, Returns: sql.TEXT, Call: call}},
+		{name: "overload", fn: sql.Function{Name: "TEST_REGISTER", Args: []sql.DataType{sql.INTEGER}, Returns: sql.TEXT, Call: call}},
+		{name: "duplicate", fn: sql.Function{Name: "Test_Register", Args: []sql.DataType{sql.TEXT}, Returns: sql.INTEGER, Call: call}, wantErr: true},
+		{name: "builtin", fn: sql.Function{Name: "abs", Args: []sql.DataType{sql.INTEGER}, Returns: sql.INTEGER, Call: call}, wantErr: true},
+		{name: "aggregate", fn: sql.Function{Name: "count", Args: []sql.DataType{sql.NULL}, Returns: sql.INTEGER, Call: call}, wantErr: true},
+		{name: "missing implementation", fn: sql.Function{Name: "test_no_call"}, wantErr: true},
+		{name: "variadic without parameters", fn: sql.Function{Name: "test_variadic", Variadic: true, Call: call}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := sql.RegisterFunction(tt.fn); (err != nil) != tt.wantErr {
+				t.Errorf("RegisterFunction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegisterFunction_Query(t *testing.T) {
+	t.Cleanup(func() { sql.UnregisterFunction("test_first") })
+	err := sql.RegisterFunction(sql.Function{
+		Name:         "test_first",
+		Args:         []sql.DataType{sql.NULL},
+		Variadic:     true,
+		CalledOnNull: true,
+		Call: func(args []sql.Value) (sql.Value, error) {
+			for _, v := range args {
+				if v != nil {
+					return v, nil
+				}
+			}
+			return nil, errors.New("no value")
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterFunction() error = %v", err)
+	}
+
+	stmt, err := sql.NewParser(strings.NewReader(`SELECT a, test_first(b, a) FROM t1 WHERE a > 2`)).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	it, err := sql.NewExecutor(&mockCatalog{}, &mockStorage{}).Query(stmt)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if err := it.Open(); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer it.Close()
+
+	var rows []sql.Row
+	for {
+		row, err := it.Next()
+		if err != nil {
+			break
+		}
+		rows = append(rows, row)
+	}
+	want := []sql.Row{{int64(3), 2.5}, {int64(4), int64(4)}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Next() got = %v, want %v", rows, want)
+	}
+}
@@ -0,0 +1,593 @@
+package sql
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+func (e Executor) compileNestedLoop(n *NestedLoopNode) (Iterator, error) {
+	outer, err := e.Compile(n.Outer)
+	if err != nil {
+		return nil, err
+	}
+	inner, err := e.Compile(n.Inner)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := joinColumns(outer.Columns(), inner.Columns())
+	pred, err := compileExpr(n.Criterion, cols)
+	if err != nil {
+		return nil, err
+	}
+	return &nestedLoopIterator{
+		kind:  n.Kind,
+		outer: outer,
+		inner: inner,
+		pred:  pred,
+		cols:  cols,
+	}, nil
+}
+
+// nestedLoopIterator joins each row of the outer input with every row of the inner input.
+// The inner input is materialized on Open.
+type nestedLoopIterator struct {
+	kind  JoinKind
+	outer Iterator
+	inner Iterator
+	pred  evalFunc
+	cols  []ResultColumn
+
+	rows      []Row
+	matched   []bool
+	row       Row
+	pos       int
+	found     bool
+	outerDone bool
+}
+
+func (it *nestedLoopIterator) Columns() []ResultColumn { return it.cols }
+
+func (it *nestedLoopIterator) Open() error {
+	if err := it.outer.Open(); err != nil {
+		return err
+	}
+	if err := it.inner.Open(); err != nil {
+		return err
+	}
+	rows, err := drain(it.inner)
+	if err != nil {
+		return err
+	}
+
+	it.rows = rows
+	it.matched = make([]bool, len(rows))
+	it.row = nil
+	it.pos = 0
+	it.outerDone = false
+	return nil
+}
+
+func (it *nestedLoopIterator) Next() (Row, error) {
+	nOuter := len(it.outer.Columns())
+	nInner := len(it.inner.Columns())
+	for {
+		if it.outerDone {
+			// Unmatched inner rows of right and full joins are emitted last.
+			for it.pos < len(it.rows) {
+				i := it.pos
+				it.pos++
+				if !it.matched[i] {
+					return joinRows(nullRow(nOuter), it.rows[i]), nil
+				}
+			}
+			return nil, io.EOF
+		}
+
+		if it.row == nil {
+			row, err := it.outer.Next()
+			if err == io.EOF {
+				it.outerDone = true
+				it.pos = 0
+				if !it.kind.keepsInner() {
+					return nil, io.EOF
+				}
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			it.row = row
+			it.pos = 0
+			it.found = false
+		}
+
+		for it.pos < len(it.rows) {
+			i := it.pos
+			it.pos++
+			joined := joinRows(it.row, it.rows[i])
+			v, err := it.pred(joined)
+			if err != nil {
+				return nil, err
+			}
+			if isTrue(v) {
+				it.found = true
+				it.matched[i] = true
+				return joined, nil
+			}
+		}
+
+		row := it.row
+		it.row = nil
+		if !it.found && it.kind.keepsOuter() {
+			return joinRows(row, nullRow(nInner)), nil
+		}
+	}
+}
+
+func (it *nestedLoopIterator) Close() error {
+	it.rows = nil
+	it.matched = nil
+	err := it.outer.Close()
+	if ierr := it.inner.Close(); err == nil {
+		err = ierr
+	}
+	return err
+}
+
+// keepsOuter reports whether the unmatched rows of the outer (left) input are part of the join.
+func (k JoinKind) keepsOuter() bool { return k == LeftOuterJoin || k == FullOuterJoin }
+
+// keepsInner reports whether the unmatched rows of the inner (right) input are part of the join.
+func (k JoinKind) keepsInner() bool { return k == RightOuterJoin || k == FullOuterJoin }
+
+func joinColumns(outer, inner []ResultColumn) []ResultColumn {
+	cols := make([]ResultColumn, 0, len(outer)+len(inner))
+	cols = append(cols, outer...)
+	return append(cols, inner...)
+}
+
+func joinRows(outer, inner Row) Row {
+	row := make(Row, 0, len(outer)+len(inner))
+	row = append(row, outer...)
+	return append(row, inner...)
+}
+
+// nullRow returns a row of n NULL values, used to pad the unmatched rows of outer joins.
+func nullRow(n int) Row {
+	return make(Row, n)
+}
+
+func (e Executor) compileHashJoin(n *HashJoinNode) (Iterator, error) {
+	outer, err := e.Compile(n.Outer)
+	if err != nil {
+		return nil, err
+	}
+	inner, err := e.Compile(n.Inner)
+	if err != nil {
+		return nil, err
+	}
+
+	outerKey, innerKey, err := compileJoinKeys(n.OuterKey, n.InnerKey, outer.Columns(), inner.Columns())
+	if err != nil {
+		return nil, err
+	}
+	return &hashJoinIterator{
+		kind:  n.Kind,
+		sides: [2]joinSide{{it: outer, key: outerKey}, {it: inner, key: innerKey}},
+		cols:  joinColumns(outer.Columns(), inner.Columns()),
+	}, nil
+}
+
+// compileJoinKeys compiles the keys of an equi-join evaluated on the outer and inner rows.
+// As for the comparison of the criterion, both keys are converted to the type they are compared as.
+func compileJoinKeys(outerKey, innerKey Expr, outerCols, innerCols []ResultColumn) (evalFunc, evalFunc, error) {
+	outer, err := compileExpr(outerKey, outerCols)
+	if err != nil {
+		return nil, nil, err
+	}
+	inner, err := compileExpr(innerKey, innerCols)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ot, it := exprType(outerKey, outerCols), exprType(innerKey, innerCols)
+	t, err := comparisonType(ot, it)
+	if err != nil {
+		return nil, nil, err
+	}
+	return implicitCoerce(outer, ot, t), implicitCoerce(inner, it, t), nil
+}
+
+// joinSide is one of the inputs of a join and the key of its rows.
+type joinSide struct {
+	it  Iterator
+	key evalFunc
+}
+
+// hashJoinIterator builds a hash table on the smaller of its inputs and probes it with the other.
+//
+// Without statistics on the inputs, the smaller one is found on Open by reading both inputs in turn
+// until one is exhausted, so at most twice the size of the smaller input is kept in memory.
+type hashJoinIterator struct {
+	kind  JoinKind
+	sides [2]joinSide
+	cols  []ResultColumn
+
+	// build is the index in sides of the input loaded in the hash table, the other one is probed.
+	build   int
+	rows    []Row
+	table   map[interface{}][]int
+	matched []bool
+
+	// probed holds the rows of the probe input read while looking for the smaller input.
+	probed    []Row
+	probeDone bool
+	row       Row
+	matches   []int
+	pos       int
+}
+
+func (it *hashJoinIterator) Columns() []ResultColumn { return it.cols }
+
+func (it *hashJoinIterator) Open() error {
+	for _, side := range it.sides {
+		if err := side.it.Open(); err != nil {
+			return err
+		}
+	}
+
+	var buffers [2][]Row
+	build := -1
+	for build == -1 {
+		for i, side := range it.sides {
+			row, err := side.it.Next()
+			if err == io.EOF {
+				build = i
+				break
+			}
+			if err != nil {
+				return err
+			}
+			buffers[i] = append(buffers[i], row)
+		}
+	}
+
+	it.build = build
+	it.rows = buffers[build]
+	it.probed = buffers[1-build]
+	it.matched = make([]bool, len(it.rows))
+	it.table = make(map[interface{}][]int)
+	for i, row := range it.rows {
+		k, err := it.sides[build].key(row)
+		if err != nil {
+			return err
+		}
+		if hk, ok := hashKey(k); ok {
+			it.table[hk] = append(it.table[hk], i)
+		}
+	}
+	it.probeDone = false
+	it.row = nil
+	it.matches = nil
+	it.pos = 0
+	return nil
+}
+
+func (it *hashJoinIterator) Next() (Row, error) {
+	for {
+		if it.probeDone {
+			if !it.keeps(it.build) {
+				return nil, io.EOF
+			}
+			for it.pos < len(it.rows) {
+				i := it.pos
+				it.pos++
+				if !it.matched[i] {
+					return it.join(nil, it.rows[i]), nil
+				}
+			}
+			return nil, io.EOF
+		}
+
+		if it.pos < len(it.matches) {
+			i := it.matches[it.pos]
+			it.pos++
+			it.matched[i] = true
+			return it.join(it.row, it.rows[i]), nil
+		}
+
+		row, err := it.nextProbe()
+		if err == io.EOF {
+			it.probeDone = true
+			it.pos = 0
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		k, err := it.sides[1-it.build].key(row)
+		if err != nil {
+			return nil, err
+		}
+		it.row = row
+		it.matches = nil
+		it.pos = 0
+		if hk, ok := hashKey(k); ok {
+			it.matches = it.table[hk]
+		}
+		if len(it.matches) == 0 && it.keeps(1-it.build) {
+			return it.join(row, nil), nil
+		}
+	}
+}
+
+func (it *hashJoinIterator) nextProbe() (Row, error) {
+	if len(it.probed) > 0 {
+		row := it.probed[0]
+		it.probed = it.probed[1:]
+		return row, nil
+	}
+	return it.sides[1-it.build].it.Next()
+}
+
+// keeps reports whether the unmatched rows of a side are part of the join.
+func (it *hashJoinIterator) keeps(side int) bool {
+	if side == 0 {
+		return it.kind.keepsOuter()
+	}
+	return it.kind.keepsInner()
+}
+
+// join combines a probe row and a build row, in the order of the outer and inner inputs.
+// A nil row is replaced by NULL values.
+func (it *hashJoinIterator) join(probe, build Row) Row {
+	if probe == nil {
+		probe = nullRow(len(it.sides[1-it.build].it.Columns()))
+	}
+	if build == nil {
+		build = nullRow(len(it.sides[it.build].it.Columns()))
+	}
+	if it.build == 0 {
+		return joinRows(build, probe)
+	}
+	return joinRows(probe, build)
+}
+
+func (it *hashJoinIterator) Close() error {
+	it.rows = nil
+	it.probed = nil
+	it.table = nil
+	it.matched = nil
+	var err error
+	for _, side := range it.sides {
+		if cerr := side.it.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// blobKey distinguishes BLOB keys from TEXT keys in hash tables.
+type blobKey string
+
+// hashKey returns a comparable key for a value, such that equal values share the same key.
+// NULL never equals any value and has no key.
+func hashKey(v Value) (interface{}, bool) {
+	switch x := v.(type) {
+	case nil:
+		return nil, false
+	case float64:
+		// Integral reals must equal their INTEGER counterpart.
+		if i := int64(x); float64(i) == x {
+			return i, true
+		}
+		return x, true
+	case []byte:
+		return blobKey(x), true
+	case time.Time:
+		// The same instant has the same key whatever its location.
+		return x.UTC(), true
+	default:
+		return x, true
+	}
+}
+
+func (e Executor) compileMergeJoin(n *MergeJoinNode) (Iterator, error) {
+	outer, err := e.Compile(n.Outer)
+	if err != nil {
+		return nil, err
+	}
+	inner, err := e.Compile(n.Inner)
+	if err != nil {
+		return nil, err
+	}
+
+	outerKey, innerKey, err := compileJoinKeys(n.OuterKey, n.InnerKey, outer.Columns(), inner.Columns())
+	if err != nil {
+		return nil, err
+	}
+	return &mergeJoinIterator{
+		kind:  n.Kind,
+		outer: mergeInput{joinSide: joinSide{it: outer, key: outerKey}},
+		inner: mergeInput{joinSide: joinSide{it: inner, key: innerKey}},
+		cols:  joinColumns(outer.Columns(), inner.Columns()),
+	}, nil
+}
+
+// mergeInput is an input of a merge join, positioned on its current row.
+type mergeInput struct {
+	joinSide
+	row    Row
+	rowKey Value
+	done   bool
+	// last is the key of the previous row, used to check the input is sorted.
+	last Value
+}
+
+// fetch moves to the next row of the input, if the current one has been consumed.
+func (in *mergeInput) fetch() error {
+	if in.row != nil || in.done {
+		return nil
+	}
+	row, err := in.it.Next()
+	if err == io.EOF {
+		in.done = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	k, err := in.key(row)
+	if err != nil {
+		return err
+	}
+	if c, err := compareValues(in.last, k); err != nil {
+		return err
+	} else if c > 0 {
+		return errors.New("merge join input is not sorted on its key")
+	}
+	in.row = row
+	in.rowKey = k
+	in.last = k
+	return nil
+}
+
+// mergeJoinIterator joins two inputs sorted on their keys by advancing on the one with the lowest key.
+// The inner rows sharing the current key are buffered to be joined with each outer row of that key.
+type mergeJoinIterator struct {
+	kind  JoinKind
+	outer mergeInput
+	inner mergeInput
+	cols  []ResultColumn
+
+	group    []Row
+	groupKey Value
+	pending  []Row
+}
+
+func (it *mergeJoinIterator) Columns() []ResultColumn { return it.cols }
+
+func (it *mergeJoinIterator) Open() error {
+	for _, in := range []*mergeInput{&it.outer, &it.inner} {
+		if err := in.it.Open(); err != nil {
+			return err
+		}
+		in.row, in.rowKey, in.last, in.done = nil, nil, nil, false
+	}
+	it.group = nil
+	it.groupKey = nil
+	it.pending = nil
+	return nil
+}
+
+func (it *mergeJoinIterator) Next() (Row, error) {
+	nOuter := len(it.outer.it.Columns())
+	nInner := len(it.inner.it.Columns())
+	for {
+		if len(it.pending) > 0 {
+			row := it.pending[0]
+			it.pending = it.pending[1:]
+			return row, nil
+		}
+
+		if err := it.outer.fetch(); err != nil {
+			return nil, err
+		}
+		if err := it.inner.fetch(); err != nil {
+			return nil, err
+		}
+
+		if it.group != nil {
+			if !it.outer.done && it.outer.rowKey != nil {
+				c, err := compareValues(it.outer.rowKey, it.groupKey)
+				if err != nil {
+					return nil, err
+				}
+				if c == 0 {
+					for _, r := range it.group {
+						it.pending = append(it.pending, joinRows(it.outer.row, r))
+					}
+					it.outer.row = nil
+					continue
+				}
+			}
+			it.group = nil
+			continue
+		}
+
+		var c int
+		switch {
+		case it.outer.done && it.inner.done:
+			return nil, io.EOF
+		case it.outer.done:
+			c = 1
+		case it.inner.done:
+			c = -1
+		case it.outer.rowKey == nil:
+			// NULL keys never match and sort first.
+			c = -1
+		case it.inner.rowKey == nil:
+			c = 1
+		default:
+			var err error
+			if c, err = compareValues(it.outer.rowKey, it.inner.rowKey); err != nil {
+				return nil, err
+			}
+		}
+
+		switch {
+		case c < 0:
+			row := it.outer.row
+			it.outer.row = nil
+			if it.kind.keepsOuter() {
+				return joinRows(row, nullRow(nInner)), nil
+			}
+		case c > 0:
+			row := it.inner.row
+			it.inner.row = nil
+			if it.kind.keepsInner() {
+				return joinRows(nullRow(nOuter), row), nil
+			}
+		default:
+			if err := it.loadGroup(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// loadGroup buffers the inner rows sharing the key of the current inner row.
+func (it *mergeJoinIterator) loadGroup() error {
+	it.groupKey = it.inner.rowKey
+	it.group = []Row{it.inner.row}
+	for {
+		it.inner.row = nil
+		if err := it.inner.fetch(); err != nil {
+			return err
+		}
+		if it.inner.done {
+			return nil
+		}
+		c, err := compareValues(it.inner.rowKey, it.groupKey)
+		if err != nil {
+			return err
+		}
+		if c != 0 {
+			return nil
+		}
+		it.group = append(it.group, it.inner.row)
+	}
+}
+
+func (it *mergeJoinIterator) Close() error {
+	it.group = nil
+	it.pending = nil
+	err := it.outer.it.Close()
+	if ierr := it.inner.it.Close(); err == nil {
+		err = ierr
+	}
+	return err
+}
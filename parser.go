@@ -21,12 +21,38 @@ func NewParser(r *strings.Reader) *Parser {
 	return &Parser{s: *NewScanner(r)}
 }
 
+// AllowBackticks makes the parser accept identifiers quoted with backticks, as in MySQL, besides double quotes.
+func (p *Parser) AllowBackticks() {
+	p.s.Backticks = true
+}
+
+// Parse parses a statement. Invalid statements are reported with a *SyntaxError.
 func (p *Parser) Parse() (*SelectStmt, error) {
-	// TODO: Add support for aliases
 	for next := parseStmtInit(p); next != nil; {
 		next = next(p)
 	}
-	return &p.stmt, p.err
+	if p.err != nil {
+		// The parser stops at the lexeme it cannot make sense of.
+		l := p.buf.l
+		msg := p.err.Error()
+		if err := p.s.Err(); err != nil && l.Token == ILLEGAL {
+			msg = err.Error()
+		}
+		return &p.stmt, &SyntaxError{Pos: l.Pos, Msg: msg, Snippet: p.s.snippet(l.Pos)}
+	}
+	return &p.stmt, nil
+}
+
+// SyntaxError reports an invalid statement.
+type SyntaxError struct {
+	Pos Pos
+	Msg string
+	// Snippet is the line of the statement holding the error, followed by a line with a caret under the error.
+	Snippet string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
 }
 
 func (p *Parser) scan() Lexeme {
@@ -66,16 +92,22 @@ func parseStmtInit(p *Parser) parseFunc {
 		p.err = fmt.Errorf("found \"%s\", expected SELECT", l.Lit)
 		return nil
 	}
+	p.stmt.Pos = l.Pos
+	if l := p.scanIgnoreWhitespace(); l.Token == DISTINCT {
+		p.stmt.Distinct = true
+	} else {
+		p.unscan()
+	}
 	return parseSelectFields
 }
 
 func parseSelectFields(p *Parser) parseFunc {
-	if l := p.scan(); l.Token == IDENT || l.Token == ASTERISK {
-		p.stmt.Fields = append(p.stmt.Fields, Ident{Name: l.Lit})
-	} else {
-		p.err = fmt.Errorf("found \"%s\", expected field", l.Lit)
+	field, err := extractSelectField(p)
+	if err != nil {
+		p.err = err
 		return nil
 	}
+	p.stmt.Fields = append(p.stmt.Fields, field)
 
 	if l := p.scan(); l.Token == COMMA {
 		return parseSelectFields(p)
@@ -86,17 +118,18 @@ func parseSelectFields(p *Parser) parseFunc {
 }
 
 func parseFrom(p *Parser) parseFunc {
-	if l := p.scan(); l.Token != FROM {
+	l := p.scan()
+	if l.Token != FROM {
 		p.err = fmt.Errorf("found \"%s\", expected FROM", l.Lit)
 		return nil
 	}
 
-	if l := p.scan(); l.Token == IDENT {
-		p.stmt.From = FromClause{TableName: &Ident{Name: l.Lit}}
-	} else {
-		p.err = fmt.Errorf("found \"%s\", expected table name", l.Lit)
+	table, err := extractTableName(p)
+	if err != nil {
+		p.err = err
 		return nil
 	}
+	p.stmt.From = FromClause{TableName: table, Pos: l.Pos}
 	// if p.peek().Token.IsTerminal() {
 	// 	return parseTerminalLexeme
 	// }
@@ -111,6 +144,8 @@ func parseFrom(p *Parser) parseFunc {
 		next = parseWhere
 	case GROUP:
 		next = parseGroupBy
+	case HAVING:
+		next = parseHaving
 	case LIMIT:
 		next = parseLimit
 	case ORDER:
@@ -124,6 +159,7 @@ func parseFrom(p *Parser) parseFunc {
 
 func parseJoin(p *Parser) parseFunc {
 	l := p.scan()
+	pos := l.Pos
 	var kind JoinKind
 	switch l.Token {
 	case JOIN:
@@ -154,12 +190,11 @@ func parseJoin(p *Parser) parseFunc {
 		kind = FullOuterJoin
 	}
 
-	l = p.scan()
-	if l.Token != IDENT {
-		p.err = fmt.Errorf("found \"%s\", expected table name", l.Lit)
+	table, err := extractTableName(p)
+	if err != nil {
+		p.err = err
 		return nil
 	}
-	t := Ident{Name: l.Lit}
 
 	l = p.scan()
 	if l.Token != ON {
@@ -167,16 +202,17 @@ func parseJoin(p *Parser) parseFunc {
 		return nil
 	}
 
-	expr, err := extractBinaryExpr(p)
+	expr, err := extractExpr(p)
 	if err != nil {
 		p.err = err
 		return nil
 	}
 
 	join := JoinSubClause{
-		TableName: &t,
+		TableName: table,
 		Kind:      kind,
 		Criterion: expr,
+		Pos:       pos,
 	}
 	p.stmt.From.Join = appendJoinSubClause(p.stmt.From.Join, join)
 
@@ -190,6 +226,8 @@ func parseJoin(p *Parser) parseFunc {
 		next = parseWhere
 	case GROUP:
 		next = parseGroupBy
+	case HAVING:
+		next = parseHaving
 	case LIMIT:
 		next = parseLimit
 	case ORDER:
@@ -229,9 +267,9 @@ func parseLimit(p *Parser) parseFunc {
 		p.err = fmt.Errorf("found \"%s\", expected LIMIT", l.Lit)
 		return nil
 	}
-	p.stmt.Limit = new(LimitClause)
+	p.stmt.Limit = &LimitClause{Pos: l.Pos}
 
-	l = p.scan()
+	l = scanNumber(p)
 	if l.Token != INT {
 		p.err = fmt.Errorf("found \"%s\", expected INT offset value", l.Lit)
 		return nil
@@ -273,18 +311,22 @@ func parseOrderBy(p *Parser) parseFunc {
 		p.err = fmt.Errorf("found \"%s %s\", expected ORDER BY", l1.Lit, l2.Lit)
 		return nil
 	}
-	p.stmt.OrderBy = new(OrderByClause)
+	p.stmt.OrderBy = &OrderByClause{Pos: l1.Pos}
 
 	return parseOrderByFields
 }
 
 func parseOrderByFields(p *Parser) parseFunc {
-	if l := p.scan(); l.Token == IDENT {
-		p.stmt.OrderBy.Fields = append(p.stmt.OrderBy.Fields, &Ident{Name: l.Lit})
-	} else {
+	if l := p.peek(); !startsExpr(l.Token) {
 		p.err = fmt.Errorf("found \"%s\", expected field", l.Lit)
 		return nil
 	}
+	field, err := extractExpr(p)
+	if err != nil {
+		p.err = err
+		return nil
+	}
+	p.stmt.OrderBy.Fields = append(p.stmt.OrderBy.Fields, field)
 
 	l := p.scan()
 	if l.Token == COMMA {
@@ -320,14 +362,19 @@ func parseGroupBy(p *Parser) parseFunc {
 		p.err = fmt.Errorf("found \"%s %s\", expected GROUP BY", l1.Lit, l2.Lit)
 		return nil
 	}
-	p.stmt.GroupBy = new(GroupByClause)
+	p.stmt.GroupBy = &GroupByClause{Pos: l1.Pos}
 
 	return parseGroupByFields
 }
 
 func parseGroupByFields(p *Parser) parseFunc {
 	if l := p.scan(); l.Token == IDENT {
-		p.stmt.GroupBy.Fields = append(p.stmt.GroupBy.Fields, &Ident{Name: l.Lit})
+		name, err := extractQualifiedName(p, l, true)
+		if err != nil {
+			p.err = err
+			return nil
+		}
+		p.stmt.GroupBy.Fields = append(p.stmt.GroupBy.Fields, name)
 	} else {
 		p.err = fmt.Errorf("found \"%s\", expected field", l.Lit)
 		return nil
@@ -342,6 +389,8 @@ func parseGroupByFields(p *Parser) parseFunc {
 	switch l.Token {
 	case EOF, SEMICOLON:
 		next = parseTerminalLexeme
+	case HAVING:
+		next = parseHaving
 	case ORDER:
 		next = parseOrderBy
 	case OFFSET:
@@ -357,13 +406,44 @@ func parseGroupByFields(p *Parser) parseFunc {
 	return next
 }
 
+func parseHaving(p *Parser) parseFunc {
+	l := p.scan()
+	if l.Token != HAVING {
+		p.err = fmt.Errorf("found \"%s\", expected HAVING", l.Lit)
+		return nil
+	}
+
+	predicate, err := extractExpr(p)
+	if err != nil {
+		p.err = err
+		return nil
+	}
+	p.stmt.Having = &HavingClause{Predicate: predicate, Pos: l.Pos}
+
+	var next parseFunc
+	switch n := p.peek(); n.Token {
+	case EOF, SEMICOLON:
+		next = parseTerminalLexeme
+	case ORDER:
+		next = parseOrderBy
+	case LIMIT:
+		next = parseLimit
+	case OFFSET:
+		next = parseOffset
+	default:
+		p.err = fmt.Errorf("found \"%s\", invalid after HAVING <predicate>", n.Lit)
+		next = nil
+	}
+	return next
+}
+
 func parseWhere(p *Parser) parseFunc {
 	l := p.scan()
 	if l.Token != WHERE {
 		p.err = fmt.Errorf("found \"%s\", expected WHERE", l.Lit)
 		return nil
 	}
-	p.stmt.Where = new(WhereClause)
+	p.stmt.Where = &WhereClause{Pos: l.Pos}
 
 	if p.peek().Token.IsTerminal() {
 		return parseTerminalLexeme
@@ -372,7 +452,7 @@ func parseWhere(p *Parser) parseFunc {
 }
 
 func parseWherePredicates(p *Parser) parseFunc {
-	predicate, err := extractLogicalExpr(p)
+	predicate, err := extractExpr(p)
 	if err != nil {
 		p.err = err
 		return nil
@@ -386,6 +466,10 @@ func parseWherePredicates(p *Parser) parseFunc {
 		next = parseTerminalLexeme
 	case GROUP:
 		next = parseGroupBy
+	case HAVING:
+		next = parseHaving
+	case ORDER:
+		next = parseOrderBy
 	case OFFSET:
 		next = parseOffset
 	case LIMIT:
@@ -413,9 +497,9 @@ func parseOffset(p *Parser) parseFunc {
 		p.err = fmt.Errorf("found \"%s\", expected OFFSET", l.Lit)
 		return nil
 	}
-	p.stmt.Offset = new(OffsetClause)
+	p.stmt.Offset = &OffsetClause{Pos: l.Pos}
 
-	l = p.scan()
+	l = scanNumber(p)
 	if l.Token != INT {
 		p.err = fmt.Errorf("found \"%s\", expected INT offset value", l.Lit)
 		return nil
@@ -453,65 +537,479 @@ func parseTerminalLexeme(p *Parser) parseFunc {
 	return nil
 }
 
-func toLiteralExpr(l Lexeme) (Expr, error) {
-	var expr Expr
+func extractSelectField(p *Parser) (Expr, error) {
+	l := p.scan()
+	if l.Token == ASTERISK {
+		return &QualifiedName{Column: l.Lit, Pos: l.Pos}, nil
+	}
+	if !startsExpr(l.Token) {
+		return nil, fmt.Errorf("found \"%s\", expected field", l.Lit)
+	}
+	p.unscan()
+
+	field, err := extractExpr(p)
+	if err != nil {
+		return nil, err
+	}
+	return extractAlias(p, field)
+}
+
+// extractTableName parses the name of a table of the FROM clause and its optional alias.
+func extractTableName(p *Parser) (Expr, error) {
+	l := p.scan()
+	if l.Token != IDENT {
+		return nil, fmt.Errorf("found \"%s\", expected table name", l.Lit)
+	}
+	name, err := extractQualifiedName(p, l, false)
+	if err != nil {
+		return nil, err
+	}
+	return extractAlias(p, name)
+}
+
+// extractQualifiedName parses a name made of parts separated by dots, starting with the part l.
+// The name of a column has up to four parts, the last one can be *, the name of a relation has up to three.
+// Keywords are accepted as the parts following a dot.
+func extractQualifiedName(p *Parser, l Lexeme, column bool) (*QualifiedName, error) {
+	name := QualifiedName{Pos: l.Pos}
+	fields := []*string{&name.Catalog, &name.Schema, &name.Table, &name.Column}
+	if !column {
+		fields = fields[:3]
+	}
+
+	parts := []string{l.Lit}
+	for l.Token != ASTERISK {
+		if n := p.scan(); n.Token != DOT {
+			p.unscan()
+			break
+		}
+		l = p.scan()
+		if l.Token != IDENT && !l.Token.IsKeyword() && (l.Token != ASTERISK || !column) {
+			return nil, fmt.Errorf("found \"%s\", expected name", l.Lit)
+		}
+		parts = append(parts, l.Lit)
+		if len(parts) > len(fields) {
+			return nil, fmt.Errorf("improper qualified name (too many dotted names): %s", strings.Join(parts, "."))
+		}
+	}
+
+	fields = fields[len(fields)-len(parts):]
+	for i, part := range parts {
+		*fields[i] = part
+	}
+	return &name, nil
+}
+
+// extractAlias parses the alias following an expression, either introduced by AS or implicit.
+// The expression is returned as is when it has no alias.
+func extractAlias(p *Parser, expr Expr) (Expr, error) {
+	l := p.scan()
+	switch l.Token {
+	case AS:
+		if l = p.scan(); l.Token != IDENT {
+			return nil, fmt.Errorf("found \"%s\", expected alias", l.Lit)
+		}
+	case IDENT:
+	default:
+		p.unscan()
+		return expr, nil
+	}
+	return &AliasExpr{Expr: expr, Alias: Ident{Name: l.Lit, Pos: l.Pos}, Pos: expr.Position()}, nil
+}
+
+// extractAggregateExpr parses the argument of a call to the aggregate function named by l,
+// the opening parenthesis has already been consumed.
+func extractAggregateExpr(p *Parser, l Lexeme, fn AggregateFunc) (Expr, error) {
+	expr := AggregateExpr{Func: fn, Pos: l.Pos}
+
+	switch l := p.scan(); {
+	case l.Token == ASTERISK && fn == CountAggregate:
+	case startsExpr(l.Token):
+		p.unscan()
+		arg, err := extractExpr(p)
+		if err != nil {
+			return nil, err
+		}
+		expr.Arg = arg
+	default:
+		return nil, fmt.Errorf("found \"%s\", expected %s argument", l.Lit, fn)
+	}
+
+	if l := p.scan(); l.Token != RPAREN {
+		return nil, fmt.Errorf("found \"%s\", expected )", l.Lit)
+	}
+	return &expr, nil
+}
+
+// extractCallExpr parses the arguments of a call to the function named by l,
+// the opening parenthesis has already been consumed.
+func extractCallExpr(p *Parser, l Lexeme) (Expr, error) {
+	expr := CallExpr{Name: l.Lit, Pos: l.Pos}
+	if l = p.scan(); l.Token == RPAREN {
+		return &expr, nil
+	}
+	p.unscan()
+
+	for {
+		arg, err := extractExpr(p)
+		if err != nil {
+			return nil, err
+		}
+		expr.Args = append(expr.Args, arg)
+
+		if l = p.scan(); l.Token == RPAREN {
+			return &expr, nil
+		} else if l.Token != COMMA {
+			return nil, fmt.Errorf("found \"%s\", expected , or )", l.Lit)
+		}
+	}
+}
+
+// extractExtractExpr parses the arguments of a call to EXTRACT named by l, the opening parenthesis has already
+// been consumed. EXTRACT(field FROM source) is the standard form of EXTRACT('field', source), which is parsed
+// as any other call.
+func extractExtractExpr(p *Parser, l Lexeme) (Expr, error) {
+	field := p.scan()
+	if field.Token != IDENT {
+		p.unscan()
+		return extractCallExpr(p, l)
+	}
+	if n := p.scan(); n.Token != FROM {
+		return nil, fmt.Errorf("found \"%s\", expected FROM", n.Lit)
+	}
+	source, err := extractExpr(p)
+	if err != nil {
+		return nil, err
+	}
+	if n := p.scan(); n.Token != RPAREN {
+		return nil, fmt.Errorf("found \"%s\", expected )", n.Lit)
+	}
+	return &CallExpr{
+		Name: l.Lit,
+		Args: []Expr{&BasicLit{Kind: STRING, Value: field.Lit, Pos: field.Pos}, source},
+		Pos:  l.Pos,
+	}, nil
+}
+
+// extractCastExpr parses the end of CAST(x AS type) starting at l, the opening parenthesis has already been consumed.
+func extractCastExpr(p *Parser, l Lexeme) (Expr, error) {
+	x, err := extractExpr(p)
+	if err != nil {
+		return nil, err
+	}
+	if l := p.scan(); l.Token != AS {
+		return nil, fmt.Errorf("found \"%s\", expected AS", l.Lit)
+	}
+	t, err := extractDataType(p)
+	if err != nil {
+		return nil, err
+	}
+	if l := p.scan(); l.Token != RPAREN {
+		return nil, fmt.Errorf("found \"%s\", expected )", l.Lit)
+	}
+	return &CastExpr{X: x, Type: t, Pos: l.Pos}, nil
+}
+
+// extractDataType parses the name of a type.
+func extractDataType(p *Parser) (DataType, error) {
+	l := p.scan()
 	if l.Token == IDENT {
-		expr = &Ident{Name: l.Lit}
-	} else if l.Token.IsLiteral() {
-		expr = &BasicLit{
-			Kind:  l.Token,
-			Value: l.Lit,
+		if t, ok := lookupDataType(l.Lit); ok {
+			return t, nil
 		}
+	}
+	return NULL, fmt.Errorf("found \"%s\", expected type", l.Lit)
+}
+
+// typedLiterals are the types which can prefix a string literal to give its type, as in DATE '2024-01-01'.
+// They are not keywords, so that columns can share their names.
+var typedLiterals = map[string]Token{
+	"DATE":      DATE,
+	"TIMESTAMP": TIMESTAMP,
+}
+
+// extractExpr parses an expression, binary operators are grouped by precedence climbing.
+func extractExpr(p *Parser) (Expr, error) {
+	return extractBinaryExpr(p, LowestPrec+1)
+}
+
+// extractBinaryExpr parses an expression made of the operators binding at least as tightly as prec.
+// Operators of the same precedence are left associative.
+func extractBinaryExpr(p *Parser, prec int) (Expr, error) {
+	var lhs Expr
+	var err error
+	if l := p.scan(); l.Token == NOT {
+		lhs, err = extractNotExpr(p, l)
 	} else {
-		return nil, fmt.Errorf("found \"%s\", expected literal", l.Lit)
+		p.unscan()
+		lhs, err = extractOperand(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.scan()
+		// A NOT following an operand negates IN, BETWEEN or LIKE.
+		not := op.Token == NOT
+		opPrec := op.Token.Precedence()
+		if not {
+			opPrec = IN.Precedence()
+		}
+		if opPrec == LowestPrec || opPrec < prec {
+			p.unscan()
+			return lhs, nil
+		}
+		if not {
+			if op = p.scan(); op.Token != IN && op.Token != BETWEEN && op.Token != LIKE {
+				return nil, fmt.Errorf("found \"%s\", expected IN, BETWEEN or LIKE", op.Lit)
+			}
+		}
+
+		switch op.Token {
+		case IS:
+			lhs, err = extractIsNullExpr(p, lhs)
+		case IN:
+			lhs, err = extractInExpr(p, lhs, not)
+		case BETWEEN:
+			lhs, err = extractBetweenExpr(p, lhs, not)
+		case LIKE:
+			lhs, err = extractLikeExpr(p, lhs, not)
+		default:
+			var rhs Expr
+			if rhs, err = extractBinaryExpr(p, opPrec+1); err == nil {
+				lhs = &BinaryExpr{LHS: lhs, Op: op.Token, RHS: rhs, Pos: lhs.Position()}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
-	return expr, nil
 }
 
-func extractLogicalExpr(p *Parser) (Expr, error) {
-	// TODO: add support for UnaryExpr
-	expr, err := extractBinaryExpr(p)
+// extractNotExpr parses the operand of the NOT keyword l, which has already been consumed.
+func extractNotExpr(p *Parser, l Lexeme) (Expr, error) {
+	x, err := extractBinaryExpr(p, NOT.UnaryPrecedence())
 	if err != nil {
 		return nil, err
 	}
+	return &UnaryExpr{Op: NOT, X: x, Pos: l.Pos}, nil
+}
 
-	var predicate Expr
-	if op := p.scan(); op.Token == AND || op.Token == OR {
-		rhs, err := extractLogicalExpr(p)
+// extractIsNullExpr parses the end of "x IS [NOT] NULL", IS has already been consumed.
+func extractIsNullExpr(p *Parser, x Expr) (Expr, error) {
+	expr := IsNullExpr{X: x, Pos: x.Position()}
+	l := p.scan()
+	if l.Token == NOT {
+		expr.Not = true
+		l = p.scan()
+	}
+	if l.Token != NULLLIT {
+		return nil, fmt.Errorf("found \"%s\", expected NULL", l.Lit)
+	}
+	return &expr, nil
+}
+
+// extractInExpr parses the list of values of "x [NOT] IN (...)", IN has already been consumed.
+func extractInExpr(p *Parser, x Expr, not bool) (Expr, error) {
+	if l := p.scan(); l.Token != LPAREN {
+		return nil, fmt.Errorf("found \"%s\", expected (", l.Lit)
+	}
+
+	expr := InExpr{X: x, Not: not, Pos: x.Position()}
+	for {
+		v, err := extractExpr(p)
 		if err != nil {
 			return nil, err
 		}
-		predicate = &BinaryExpr{LHS: &expr, Op: op.Token, RHS: rhs}
-	} else {
+		expr.List = append(expr.List, v)
+
+		if l := p.scan(); l.Token == RPAREN {
+			return &expr, nil
+		} else if l.Token != COMMA {
+			return nil, fmt.Errorf("found \"%s\", expected , or )", l.Lit)
+		}
+	}
+}
+
+// extractBetweenExpr parses the bounds of "x [NOT] BETWEEN low AND high", BETWEEN has already been consumed.
+// The bounds cannot contain operators binding looser than BETWEEN, so that the AND separating them
+// is not mistaken for a conjunction.
+func extractBetweenExpr(p *Parser, x Expr, not bool) (Expr, error) {
+	prec := BETWEEN.Precedence() + 1
+	low, err := extractBinaryExpr(p, prec)
+	if err != nil {
+		return nil, err
+	}
+	if l := p.scan(); l.Token != AND {
+		return nil, fmt.Errorf("found \"%s\", expected AND", l.Lit)
+	}
+	high, err := extractBinaryExpr(p, prec)
+	if err != nil {
+		return nil, err
+	}
+	return &BetweenExpr{X: x, Low: low, High: high, Not: not, Pos: x.Position()}, nil
+}
+
+// extractLikeExpr parses the pattern and the optional escape character of "x [NOT] LIKE pattern [ESCAPE escape]",
+// LIKE has already been consumed.
+func extractLikeExpr(p *Parser, x Expr, not bool) (Expr, error) {
+	prec := LIKE.Precedence() + 1
+	pattern, err := extractBinaryExpr(p, prec)
+	if err != nil {
+		return nil, err
+	}
+	expr := LikeExpr{X: x, Pattern: pattern, Not: not, Pos: x.Position()}
+
+	if l := p.scan(); l.Token != ESCAPE {
 		p.unscan()
-		predicate = &expr
+		return &expr, nil
+	}
+	if expr.Escape, err = extractBinaryExpr(p, prec); err != nil {
+		return nil, err
 	}
-	return predicate, nil
+	return &expr, nil
 }
 
-func extractBinaryExpr(p *Parser) (BinaryExpr, error) {
-	var expr BinaryExpr
-	l := p.scan()
-	if lhs, err := toLiteralExpr(l); err == nil {
-		expr.LHS = lhs
-	} else {
-		return expr, err
+// extractCaseExpr parses a CASE expression up to its END keyword, the CASE keyword l has already been consumed.
+func extractCaseExpr(p *Parser, l Lexeme) (Expr, error) {
+	expr := CaseExpr{Pos: l.Pos}
+	if l := p.peek(); l.Token != WHEN {
+		operand, err := extractExpr(p)
+		if err != nil {
+			return nil, err
+		}
+		expr.Operand = operand
 	}
 
-	l = p.scan()
-	if !l.Token.IsComparisonOperator() {
-		return expr, fmt.Errorf("found \"%s\", expected comparison operator", l.Lit)
+	for {
+		l := p.scan()
+		switch {
+		case l.Token == WHEN && expr.Else == nil:
+			when := l.Pos
+			cond, err := extractExpr(p)
+			if err != nil {
+				return nil, err
+			}
+			if l = p.scan(); l.Token != THEN {
+				return nil, fmt.Errorf("found \"%s\", expected THEN", l.Lit)
+			}
+			result, err := extractExpr(p)
+			if err != nil {
+				return nil, err
+			}
+			expr.Whens = append(expr.Whens, &WhenClause{Cond: cond, Result: result, Pos: when})
+		case l.Token == ELSE && len(expr.Whens) > 0 && expr.Else == nil:
+			result, err := extractExpr(p)
+			if err != nil {
+				return nil, err
+			}
+			expr.Else = result
+		case l.Token == END && len(expr.Whens) > 0:
+			return &expr, nil
+		case len(expr.Whens) == 0:
+			return nil, fmt.Errorf("found \"%s\", expected WHEN", l.Lit)
+		default:
+			return nil, fmt.Errorf("found \"%s\", expected END", l.Lit)
+		}
 	}
-	expr.Op = l.Token
+}
 
-	l = p.scan()
-	rhs, err := toLiteralExpr(l)
+// extractOperand parses an operand of an expression followed by any number of casts with the :: notation.
+func extractOperand(p *Parser) (Expr, error) {
+	x, err := extractPrimaryExpr(p)
 	if err != nil {
-		return expr, err
+		return nil, err
 	}
-	expr.RHS = rhs
+	for {
+		if l := p.scan(); l.Token != TYPECAST {
+			p.unscan()
+			return x, nil
+		}
+		t, err := extractDataType(p)
+		if err != nil {
+			return nil, err
+		}
+		x = &CastExpr{X: x, Type: t, Pos: x.Position()}
+	}
+}
 
-	return expr, nil
+// extractPrimaryExpr parses a literal, a column, a function call, an aggregate, a cast, a signed operand
+// or an expression enclosed in parentheses.
+func extractPrimaryExpr(p *Parser) (Expr, error) {
+	l := p.scan()
+	switch {
+	case l.Token == LPAREN:
+		expr, err := extractExpr(p)
+		if err != nil {
+			return nil, err
+		}
+		if l = p.scan(); l.Token != RPAREN {
+			return nil, fmt.Errorf("found \"%s\", expected )", l.Lit)
+		}
+		return expr, nil
+	case l.Token == IDENT:
+		n := p.scan()
+		if n.Token == LPAREN {
+			if strings.EqualFold(l.Lit, "EXTRACT") {
+				return extractExtractExpr(p, l)
+			}
+			if fn, ok := lookupAggregateFunc(l.Lit); ok {
+				return extractAggregateExpr(p, l, fn)
+			}
+			return extractCallExpr(p, l)
+		}
+		if kind, ok := typedLiterals[strings.ToUpper(l.Lit)]; ok && n.Token == STRING {
+			return &BasicLit{Kind: kind, Value: n.Lit, Pos: l.Pos}, nil
+		}
+		p.unscan()
+		return extractQualifiedName(p, l, true)
+	case l.Token == CASE:
+		return extractCaseExpr(p, l)
+	case l.Token == CAST:
+		if n := p.scan(); n.Token != LPAREN {
+			return nil, fmt.Errorf("found \"%s\", expected (", n.Lit)
+		}
+		return extractCastExpr(p, l)
+	case l.Token.IsLiteral():
+		return &BasicLit{Kind: l.Token, Value: l.Lit, Pos: l.Pos}, nil
+	case l.Token == MINUS || l.Token == PLUS:
+		x, err := extractOperand(p)
+		if err != nil {
+			return nil, err
+		}
+		// Negative numbers are literals, so that the smallest INTEGER can be written: its absolute value
+		// overflows an INTEGER and is scanned as a FLOAT.
+		if lit, ok := x.(*BasicLit); ok && l.Token == MINUS && (lit.Kind == INT || lit.Kind == FLOAT) && !strings.HasPrefix(lit.Value, "-") {
+			neg := BasicLit{Kind: lit.Kind, Value: "-" + lit.Value, Pos: l.Pos}
+			if _, err := strconv.ParseInt(neg.Value, 10, 64); err == nil {
+				neg.Kind = INT
+			}
+			return &neg, nil
+		}
+		return &UnaryExpr{Op: l.Token, X: x, Pos: l.Pos}, nil
+	default:
+		return nil, fmt.Errorf("found \"%s\", expected expression", l.Lit)
+	}
+}
+
+// startsExpr checks if an expression can start with the token.
+func startsExpr(t Token) bool {
+	return t == IDENT || t == LPAREN || t == MINUS || t == PLUS || t == NOT || t == CASE || t == CAST || t.IsLiteral()
+}
+
+// scanNumber scans a number, a leading minus sign is part of the literal.
+func scanNumber(p *Parser) Lexeme {
+	l := p.scan()
+	if l.Token != MINUS {
+		return l
+	}
+	n := p.scan()
+	if n.Token != INT && n.Token != FLOAT {
+		p.unscan()
+		return l
+	}
+	return Lexeme{Token: n.Token, Lit: "-" + n.Lit, Pos: l.Pos}
 }
 
 func skipOuterJoinKeywords(p *Parser) error {
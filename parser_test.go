@@ -1,6 +1,7 @@
 package sql_test
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -20,22 +21,110 @@ func TestParser_ParseStatement(t *testing.T) {
 		{
 			s: `SELECT name FROM tbl`,
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "name"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "name"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "tbl"},
+					TableName: &sql.QualifiedName{Table: "tbl"},
 				},
 				// Fields:    []string{"name"},
 				// From: "tbl",
 			},
 		},
 
+		// Comments
+		{
+			s: "-- header\n/* block\n   comment */\nSELECT a - -1 -- trailing\nFROM tbl;",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "a"}, Op: sql.MINUS, RHS: &sql.BasicLit{Kind: sql.INT, Value: "-1"}}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "tbl"},
+				},
+			},
+		},
+		{
+			s: `SELECT -9223372036854775808, -1.5 FROM tbl`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.BasicLit{Kind: sql.INT, Value: "-9223372036854775808"}, &sql.BasicLit{Kind: sql.FLOAT, Value: "-1.5"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "tbl"},
+				},
+			},
+		},
+
+		// String literals
+		{
+			s: `SELECT 'it''s' || E'\tx' AS s FROM tbl`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.AliasExpr{
+					Expr:  &sql.BinaryExpr{LHS: &sql.BasicLit{Kind: sql.STRING, Value: "it's"}, Op: sql.CONCAT, RHS: &sql.BasicLit{Kind: sql.STRING, Value: "\tx"}},
+					Alias: sql.Ident{Name: "s"},
+				}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "tbl"},
+				},
+			},
+		},
+
+		// Quoted identifiers
+		{
+			s: `SELECT "order", t."First Name" AS "select" FROM "my table" AS t WHERE "from" IS NULL`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.QualifiedName{Column: "order"},
+					&sql.AliasExpr{Expr: &sql.QualifiedName{Table: "t", Column: "First Name"}, Alias: sql.Ident{Name: "select"}},
+				},
+				From: sql.FromClause{
+					TableName: &sql.AliasExpr{Expr: &sql.QualifiedName{Table: "my table"}, Alias: sql.Ident{Name: "t"}},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.IsNullExpr{X: &sql.QualifiedName{Column: "from"}},
+				},
+			},
+		},
+		{
+			s: `SELECT "first.name", t."a.b" FROM "my.table" AS t`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.QualifiedName{Column: "first.name"},
+					&sql.QualifiedName{Table: "t", Column: "a.b"},
+				},
+				From: sql.FromClause{
+					TableName: &sql.AliasExpr{Expr: &sql.QualifiedName{Table: "my.table"}, Alias: sql.Ident{Name: "t"}},
+				},
+			},
+		},
+
+		// Qualified names
+		{
+			s: `SELECT db.s."my table".a, t.*, u.end FROM db.s."my table" JOIN s.t ON t.a = a GROUP BY t.a, u.b`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.QualifiedName{Catalog: "db", Schema: "s", Table: "my table", Column: "a"},
+					&sql.QualifiedName{Table: "t", Column: "*"},
+					&sql.QualifiedName{Table: "u", Column: "end"},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Catalog: "db", Schema: "s", Table: "my table"},
+					Join: &sql.JoinSubClause{
+						TableName: &sql.QualifiedName{Schema: "s", Table: "t"},
+						Kind:      sql.InnerJoin,
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "t", Column: "a"},
+							Op:  sql.EQ,
+							RHS: &sql.QualifiedName{Column: "a"},
+						},
+					},
+				},
+				GroupBy: &sql.GroupByClause{Fields: []*sql.QualifiedName{{Table: "t", Column: "a"}, {Table: "u", Column: "b"}}},
+			},
+		},
+
 		// Multi-field statement
 		{
 			s: `SELECT first_name, last_name, age FROM my_table`,
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "first_name"}, {Name: "last_name"}, {Name: "age"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "first_name"}, &sql.QualifiedName{Column: "last_name"}, &sql.QualifiedName{Column: "age"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "my_table"},
+					TableName: &sql.QualifiedName{Table: "my_table"},
 				},
 				// Fields:    []string{"first_name", "last_name", "age"},
 				// From: "my_table",
@@ -50,9 +139,9 @@ func TestParser_ParseStatement(t *testing.T) {
 
 FROM my_table`,
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "first_name"}, {Name: "last_name"}, {Name: "age"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "first_name"}, &sql.QualifiedName{Column: "last_name"}, &sql.QualifiedName{Column: "age"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "my_table"},
+					TableName: &sql.QualifiedName{Table: "my_table"},
 				},
 			},
 		},
@@ -60,11 +149,11 @@ FROM my_table`,
 		{
 			s: `SELECT first_name, last_name, age FROM my_table ORDER BY age OFFSET 100 LIMIT 4`,
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "first_name"}, {Name: "last_name"}, {Name: "age"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "first_name"}, &sql.QualifiedName{Column: "last_name"}, &sql.QualifiedName{Column: "age"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "my_table"},
+					TableName: &sql.QualifiedName{Table: "my_table"},
 				},
-				OrderBy: &sql.OrderByClause{Fields: []*sql.Ident{{Name: "age"}}},
+				OrderBy: &sql.OrderByClause{Fields: []sql.Expr{&sql.QualifiedName{Column: "age"}}},
 				Limit:   &sql.LimitClause{Value: 4},
 				Offset:  &sql.OffsetClause{Value: 100},
 			},
@@ -74,12 +163,125 @@ FROM my_table`,
 		{
 			s: `SELECT first_name, last_name, age FROM my_table GROUP BY first_name, last_name`,
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "first_name"}, {Name: "last_name"}, {Name: "age"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "first_name"}, &sql.QualifiedName{Column: "last_name"}, &sql.QualifiedName{Column: "age"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "my_table"},
+					TableName: &sql.QualifiedName{Table: "my_table"},
 				},
 				GroupBy: &sql.GroupByClause{
-					Fields: []*sql.Ident{{Name: "first_name"}, {Name: "last_name"}},
+					Fields: []*sql.QualifiedName{{Column: "first_name"}, {Column: "last_name"}},
+				},
+			},
+		},
+
+		// Distinct statement
+		{
+			s: `SELECT DISTINCT last_name FROM my_table`,
+			stmt: &sql.SelectStmt{
+				Distinct: true,
+				Fields:   []sql.Expr{&sql.QualifiedName{Column: "last_name"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+			},
+		},
+
+		// Arithmetic statement
+		{
+			s: `SELECT price * qty AS total, a + b * c - -d, -1, first || ' ' || last FROM my_table WHERE a % 2 = 1`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.AliasExpr{
+						Expr:  &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "price"}, Op: sql.ASTERISK, RHS: &sql.QualifiedName{Column: "qty"}},
+						Alias: sql.Ident{Name: "total"},
+					},
+					&sql.BinaryExpr{
+						LHS: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Column: "a"},
+							Op:  sql.PLUS,
+							RHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "b"}, Op: sql.ASTERISK, RHS: &sql.QualifiedName{Column: "c"}},
+						},
+						Op:  sql.MINUS,
+						RHS: &sql.UnaryExpr{Op: sql.MINUS, X: &sql.QualifiedName{Column: "d"}},
+					},
+					&sql.BasicLit{Kind: sql.INT, Value: "-1"},
+					&sql.BinaryExpr{
+						LHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "first"}, Op: sql.CONCAT, RHS: &sql.BasicLit{Kind: sql.STRING, Value: " "}},
+						Op:  sql.CONCAT,
+						RHS: &sql.QualifiedName{Column: "last"},
+					},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "a"}, Op: sql.PERCENT, RHS: &sql.BasicLit{Kind: sql.INT, Value: "2"}},
+						Op:  sql.EQ,
+						RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
+					},
+				},
+			},
+		},
+
+		// Aggregate statement
+		{
+			s: `SELECT last_name, COUNT(*), max(age) FROM my_table GROUP BY last_name`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.QualifiedName{Column: "last_name"},
+					&sql.AggregateExpr{Func: sql.CountAggregate},
+					&sql.AggregateExpr{Func: sql.MaxAggregate, Arg: &sql.QualifiedName{Column: "age"}},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+				GroupBy: &sql.GroupByClause{
+					Fields: []*sql.QualifiedName{{Column: "last_name"}},
+				},
+			},
+		},
+
+		// Having statement
+		{
+			s: `SELECT last_name, COUNT(*) FROM my_table GROUP BY last_name HAVING COUNT(*) > 1 ORDER BY last_name`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.QualifiedName{Column: "last_name"},
+					&sql.AggregateExpr{Func: sql.CountAggregate},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+				GroupBy: &sql.GroupByClause{
+					Fields: []*sql.QualifiedName{{Column: "last_name"}},
+				},
+				Having: &sql.HavingClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.AggregateExpr{Func: sql.CountAggregate},
+						Op:  sql.GT,
+						RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
+					},
+				},
+				OrderBy: &sql.OrderByClause{
+					Fields: []sql.Expr{&sql.QualifiedName{Column: "last_name"}},
+				},
+			},
+		},
+
+		// Having statement without group by
+		{
+			s: `SELECT COUNT(*) FROM my_table HAVING COUNT(*) > 1`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.AggregateExpr{Func: sql.CountAggregate}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+				Having: &sql.HavingClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.AggregateExpr{Func: sql.CountAggregate},
+						Op:  sql.GT,
+						RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
+					},
 				},
 			},
 		},
@@ -88,12 +290,12 @@ FROM my_table`,
 		{
 			s: `SELECT first_name, last_name, age FROM my_table ORDER BY first_name, last_name`,
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "first_name"}, {Name: "last_name"}, {Name: "age"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "first_name"}, &sql.QualifiedName{Column: "last_name"}, &sql.QualifiedName{Column: "age"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "my_table"},
+					TableName: &sql.QualifiedName{Table: "my_table"},
 				},
 				OrderBy: &sql.OrderByClause{
-					Fields: []*sql.Ident{{Name: "first_name"}, {Name: "last_name"}},
+					Fields: []sql.Expr{&sql.QualifiedName{Column: "first_name"}, &sql.QualifiedName{Column: "last_name"}},
 				},
 			},
 		},
@@ -104,47 +306,293 @@ FROM my_table`,
 					FROM my_table 
 					WHERE first_name = 1 AND last_name <> 'TEST' OR age > 18;`,
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "first_name"}, {Name: "last_name"}, {Name: "age"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "first_name"}, &sql.QualifiedName{Column: "last_name"}, &sql.QualifiedName{Column: "age"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "my_table"},
+					TableName: &sql.QualifiedName{Table: "my_table"},
 				},
 				Where: &sql.WhereClause{
 					Predicate: &sql.BinaryExpr{
 						LHS: &sql.BinaryExpr{
-							LHS: &sql.Ident{Name: "first_name"},
-							Op:  sql.EQ,
-							RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
+							LHS: &sql.BinaryExpr{
+								LHS: &sql.QualifiedName{Column: "first_name"},
+								Op:  sql.EQ,
+								RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
+							},
+							Op: sql.AND,
+							RHS: &sql.BinaryExpr{
+								LHS: &sql.QualifiedName{Column: "last_name"},
+								Op:  sql.NEQ,
+								RHS: &sql.BasicLit{Kind: sql.STRING, Value: "TEST"},
+							},
+						},
+						Op: sql.OR,
+						RHS: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Column: "age"},
+							Op:  sql.GT,
+							RHS: &sql.BasicLit{Kind: sql.INT, Value: "18"},
+						},
+					},
+				},
+			},
+		},
+
+		// Where with parentheses
+		{
+			s: `SELECT a FROM my_table WHERE (a = 1 OR b = 2) AND ((c) = d OR e > 3) ORDER BY a`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.BinaryExpr{
+							LHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "a"}, Op: sql.EQ, RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"}},
+							Op:  sql.OR,
+							RHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "b"}, Op: sql.EQ, RHS: &sql.BasicLit{Kind: sql.INT, Value: "2"}},
 						},
 						Op: sql.AND,
 						RHS: &sql.BinaryExpr{
-							LHS: &sql.BinaryExpr{
-								LHS: &sql.Ident{Name: "last_name"},
-								Op:  sql.NEQ,
-								RHS: &sql.BasicLit{Kind: sql.STRING, Value: "'TEST'"},
+							LHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "c"}, Op: sql.EQ, RHS: &sql.QualifiedName{Column: "d"}},
+							Op:  sql.OR,
+							RHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "e"}, Op: sql.GT, RHS: &sql.BasicLit{Kind: sql.INT, Value: "3"}},
+						},
+					},
+				},
+				OrderBy: &sql.OrderByClause{Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}}},
+			},
+		},
+
+		// Where with NOT and IS NULL
+		{
+			s: `SELECT a FROM my_table WHERE NOT a = 1 AND b IS NOT NULL OR NOT (c IS NULL)`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.BinaryExpr{
+							LHS: &sql.UnaryExpr{
+								Op: sql.NOT,
+								X:  &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "a"}, Op: sql.EQ, RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"}},
 							},
-							Op: sql.OR,
-							RHS: &sql.BinaryExpr{
-								LHS: &sql.Ident{Name: "age"},
-								Op:  sql.GT,
-								RHS: &sql.BasicLit{Kind: sql.INT, Value: "18"},
+							Op:  sql.AND,
+							RHS: &sql.IsNullExpr{X: &sql.QualifiedName{Column: "b"}, Not: true},
+						},
+						Op: sql.OR,
+						RHS: &sql.UnaryExpr{
+							Op: sql.NOT,
+							X:  &sql.IsNullExpr{X: &sql.QualifiedName{Column: "c"}},
+						},
+					},
+				},
+			},
+		},
+
+		// IS NULL on a comparison
+		{
+			s: `SELECT a = NULL IS NULL FROM my_table`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.IsNullExpr{
+						X: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "a"}, Op: sql.EQ, RHS: &sql.BasicLit{Kind: sql.NULLLIT, Value: "NULL"}},
+					},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+			},
+		},
+
+		// Where with IN, BETWEEN and LIKE
+		{
+			s: `SELECT a FROM my_table WHERE a NOT IN (1, b + 1) AND b BETWEEN 1 AND 2 + 1 AND c NOT LIKE 'A!%%' ESCAPE '!'`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.BinaryExpr{
+							LHS: &sql.InExpr{
+								X: &sql.QualifiedName{Column: "a"},
+								List: []sql.Expr{
+									&sql.BasicLit{Kind: sql.INT, Value: "1"},
+									&sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "b"}, Op: sql.PLUS, RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"}},
+								},
+								Not: true,
+							},
+							Op: sql.AND,
+							RHS: &sql.BetweenExpr{
+								X:    &sql.QualifiedName{Column: "b"},
+								Low:  &sql.BasicLit{Kind: sql.INT, Value: "1"},
+								High: &sql.BinaryExpr{LHS: &sql.BasicLit{Kind: sql.INT, Value: "2"}, Op: sql.PLUS, RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"}},
 							},
 						},
+						Op: sql.AND,
+						RHS: &sql.LikeExpr{
+							X:       &sql.QualifiedName{Column: "c"},
+							Pattern: &sql.BasicLit{Kind: sql.STRING, Value: "A!%%"},
+							Escape:  &sql.BasicLit{Kind: sql.STRING, Value: "!"},
+							Not:     true,
+						},
 					},
 				},
 			},
 		},
 
+		// Case expressions
+		{
+			s: `SELECT CASE WHEN a < 1 THEN 'low' WHEN a < 10 THEN 'mid' ELSE 'high' END AS bucket FROM my_table ORDER BY CASE b WHEN 1 THEN 0 END, a`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.AliasExpr{
+						Expr: &sql.CaseExpr{
+							Whens: []*sql.WhenClause{
+								{
+									Cond:   &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "a"}, Op: sql.LT, RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"}},
+									Result: &sql.BasicLit{Kind: sql.STRING, Value: "low"},
+								},
+								{
+									Cond:   &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "a"}, Op: sql.LT, RHS: &sql.BasicLit{Kind: sql.INT, Value: "10"}},
+									Result: &sql.BasicLit{Kind: sql.STRING, Value: "mid"},
+								},
+							},
+							Else: &sql.BasicLit{Kind: sql.STRING, Value: "high"},
+						},
+						Alias: sql.Ident{Name: "bucket"},
+					},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+				OrderBy: &sql.OrderByClause{
+					Fields: []sql.Expr{
+						&sql.CaseExpr{
+							Operand: &sql.QualifiedName{Column: "b"},
+							Whens: []*sql.WhenClause{
+								{Cond: &sql.BasicLit{Kind: sql.INT, Value: "1"}, Result: &sql.BasicLit{Kind: sql.INT, Value: "0"}},
+							},
+						},
+						&sql.QualifiedName{Column: "a"},
+					},
+				},
+			},
+		},
+
+		// Function calls
+		{
+			s: `SELECT round(a * 2.5, 1), now() FROM my_table WHERE ABS(b - 1) > 2`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.CallExpr{
+						Name: "round",
+						Args: []sql.Expr{
+							&sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "a"}, Op: sql.ASTERISK, RHS: &sql.BasicLit{Kind: sql.FLOAT, Value: "2.5"}},
+							&sql.BasicLit{Kind: sql.INT, Value: "1"},
+						},
+					},
+					&sql.CallExpr{Name: "now"},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.CallExpr{
+							Name: "ABS",
+							Args: []sql.Expr{&sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "b"}, Op: sql.MINUS, RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"}}},
+						},
+						Op:  sql.GT,
+						RHS: &sql.BasicLit{Kind: sql.INT, Value: "2"},
+					},
+				},
+			},
+		},
+
+		// Datetime literals
+		{
+			s: `SELECT date, EXTRACT(year FROM date) FROM my_table WHERE date >= DATE '2024-01-01' AND date < timestamp '2024-06-01 12:00:00'`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.QualifiedName{Column: "date"},
+					&sql.CallExpr{Name: "EXTRACT", Args: []sql.Expr{&sql.BasicLit{Kind: sql.STRING, Value: "year"}, &sql.QualifiedName{Column: "date"}}},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "date"}, Op: sql.GTE, RHS: &sql.BasicLit{Kind: sql.DATE, Value: "2024-01-01"}},
+						Op:  sql.AND,
+						RHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "date"}, Op: sql.LT, RHS: &sql.BasicLit{Kind: sql.TIMESTAMP, Value: "2024-06-01 12:00:00"}},
+					},
+				},
+			},
+		},
+
+		// Casts
+		{
+			s: `SELECT "cast"(a) FROM my_table`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.CallExpr{Name: "cast", Args: []sql.Expr{&sql.QualifiedName{Column: "a"}}}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+			},
+		},
+		{
+			s: `SELECT CAST(a AS integer), b::text::INT, -c::Real FROM my_table WHERE d::timestamp > CAST('2024-01-01' AS DATETIME)`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.CastExpr{X: &sql.QualifiedName{Column: "a"}, Type: sql.INTEGER},
+					&sql.CastExpr{X: &sql.CastExpr{X: &sql.QualifiedName{Column: "b"}, Type: sql.TEXT}, Type: sql.INTEGER},
+					&sql.UnaryExpr{Op: sql.MINUS, X: &sql.CastExpr{X: &sql.QualifiedName{Column: "c"}, Type: sql.REAL}},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.CastExpr{X: &sql.QualifiedName{Column: "d"}, Type: sql.DATETIME},
+						Op:  sql.GT,
+						RHS: &sql.CastExpr{X: &sql.BasicLit{Kind: sql.STRING, Value: "2024-01-01"}, Type: sql.DATETIME},
+					},
+				},
+			},
+		},
+
+		// Nested comparison operands
+		{
+			s: `SELECT (a = b) = (c < d) FROM my_table`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.BinaryExpr{
+						LHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "a"}, Op: sql.EQ, RHS: &sql.QualifiedName{Column: "b"}},
+						Op:  sql.EQ,
+						RHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "c"}, Op: sql.LT, RHS: &sql.QualifiedName{Column: "d"}},
+					},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+			},
+		},
+
 		// Where statement
 		{
 			s: `SELECT first_name, last_name, age FROM my_table WHERE first_name = 1`,
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "first_name"}, {Name: "last_name"}, {Name: "age"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "first_name"}, &sql.QualifiedName{Column: "last_name"}, &sql.QualifiedName{Column: "age"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "my_table"},
+					TableName: &sql.QualifiedName{Table: "my_table"},
 				},
 				Where: &sql.WhereClause{
 					Predicate: &sql.BinaryExpr{
-						LHS: &sql.Ident{Name: "first_name"},
+						LHS: &sql.QualifiedName{Column: "first_name"},
 						Op:  sql.EQ,
 						RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
 					},
@@ -152,29 +600,55 @@ FROM my_table`,
 			},
 		},
 
-		// Select all statement
-		// {
-		// 	s: `SELECT a as b FROM my_table`,
-		// 	stmt: &sql.SelectStmt{
-		// 		Fields:    []sql.Expr{&sql.AliasExpr{Expr: &sql.Ident{Name: "a"}, Alias: sql.Ident{Name: "b"}}},
-		// 		From: sql.Ident{Name: "my_table"},
-		// 	},
-		// },
+		// Alias statement
+		{
+			s: `SELECT a as b, c d, COUNT(*) AS n FROM my_table`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.AliasExpr{Expr: &sql.QualifiedName{Column: "a"}, Alias: sql.Ident{Name: "b"}},
+					&sql.AliasExpr{Expr: &sql.QualifiedName{Column: "c"}, Alias: sql.Ident{Name: "d"}},
+					&sql.AliasExpr{Expr: &sql.AggregateExpr{Func: sql.CountAggregate}, Alias: sql.Ident{Name: "n"}},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "my_table"},
+				},
+			},
+		},
+
+		// Table alias statement
+		{
+			s: `SELECT x.a, y.b FROM t1 AS x JOIN t2 y ON x.a = y.c`,
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Table: "x", Column: "a"}, &sql.QualifiedName{Table: "y", Column: "b"}},
+				From: sql.FromClause{
+					TableName: &sql.AliasExpr{Expr: &sql.QualifiedName{Table: "t1"}, Alias: sql.Ident{Name: "x"}},
+					Join: &sql.JoinSubClause{
+						TableName: &sql.AliasExpr{Expr: &sql.QualifiedName{Table: "t2"}, Alias: sql.Ident{Name: "y"}},
+						Kind:      sql.InnerJoin,
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "x", Column: "a"},
+							Op:  sql.EQ,
+							RHS: &sql.QualifiedName{Table: "y", Column: "c"},
+						},
+					},
+				},
+			},
+		},
 
 		// Join statement
 		{
 			s: `SELECT t1.a, t2.b FROM t1 JOIN t2 ON t1.a = t2.c`,
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "t1.a"}, {Name: "t2.b"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}, &sql.QualifiedName{Table: "t2", Column: "b"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 					Join: &sql.JoinSubClause{
-						TableName: &sql.Ident{Name: "t2"},
+						TableName: &sql.QualifiedName{Table: "t2"},
 						Kind:      sql.InnerJoin,
-						Criterion: sql.BinaryExpr{
-							LHS: &sql.Ident{Name: "t1.a"},
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
 							Op:  sql.EQ,
-							RHS: &sql.Ident{Name: "t2.c"},
+							RHS: &sql.QualifiedName{Table: "t2", Column: "c"},
 						},
 					},
 				},
@@ -190,40 +664,40 @@ FROM my_table`,
 					FULL JOIN t4 ON t1.a = t4.b
 					RIGHT OUTER JOIN t5 ON t3.c = t5.x`,
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "t1.a"}, {Name: "t2.b"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}, &sql.QualifiedName{Table: "t2", Column: "b"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 					Join: &sql.JoinSubClause{
-						TableName: &sql.Ident{Name: "t2"},
+						TableName: &sql.QualifiedName{Table: "t2"},
 						Kind:      sql.InnerJoin,
-						Criterion: sql.BinaryExpr{
-							LHS: &sql.Ident{Name: "t1.a"},
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
 							Op:  sql.EQ,
-							RHS: &sql.Ident{Name: "t2.c"},
+							RHS: &sql.QualifiedName{Table: "t2", Column: "c"},
 						},
 						Join: &sql.JoinSubClause{
-							TableName: &sql.Ident{Name: "t3"},
+							TableName: &sql.QualifiedName{Table: "t3"},
 							Kind:      sql.LeftOuterJoin,
-							Criterion: sql.BinaryExpr{
-								LHS: &sql.Ident{Name: "t2.d"},
+							Criterion: &sql.BinaryExpr{
+								LHS: &sql.QualifiedName{Table: "t2", Column: "d"},
 								Op:  sql.EQ,
-								RHS: &sql.Ident{Name: "t3.a"},
+								RHS: &sql.QualifiedName{Table: "t3", Column: "a"},
 							},
 							Join: &sql.JoinSubClause{
-								TableName: &sql.Ident{Name: "t4"},
+								TableName: &sql.QualifiedName{Table: "t4"},
 								Kind:      sql.FullOuterJoin,
-								Criterion: sql.BinaryExpr{
-									LHS: &sql.Ident{Name: "t1.a"},
+								Criterion: &sql.BinaryExpr{
+									LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
 									Op:  sql.EQ,
-									RHS: &sql.Ident{Name: "t4.b"},
+									RHS: &sql.QualifiedName{Table: "t4", Column: "b"},
 								},
 								Join: &sql.JoinSubClause{
-									TableName: &sql.Ident{Name: "t5"},
+									TableName: &sql.QualifiedName{Table: "t5"},
 									Kind:      sql.RightOuterJoin,
-									Criterion: sql.BinaryExpr{
-										LHS: &sql.Ident{Name: "t3.c"},
+									Criterion: &sql.BinaryExpr{
+										LHS: &sql.QualifiedName{Table: "t3", Column: "c"},
 										Op:  sql.EQ,
-										RHS: &sql.Ident{Name: "t5.x"},
+										RHS: &sql.QualifiedName{Table: "t5", Column: "x"},
 									},
 								},
 							},
@@ -237,24 +711,61 @@ FROM my_table`,
 		{
 			s: `SELECT * FROM my_table`,
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "*"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "*"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "my_table"},
+					TableName: &sql.QualifiedName{Table: "my_table"},
 				},
 			},
 		},
 
 		// Errors
-		{s: `foo`, err: `found "foo", expected SELECT`},
-		{s: `SELECT !`, err: `found "!", expected field`},
-		{s: `SELECT field xxx`, err: `found "xxx", expected FROM`},
-		{s: `SELECT field FROM *`, err: `found "*", expected table name`},
-		{s: `SELECT field FROM table OFFSET 1`, err: `found "OFFSET", invalid after FROM <table>`},
-		{s: `SELECT field FROM table ORDER BY field OFFSET 1.5`, err: `found "1.5", expected INT offset value`},
-		{s: `SELECT field FROM table ORDER BY field OFFSET -1`, err: `found "-1", expected nonnegative INT`},
-		{s: `SELECT field FROM table LIMIT -1`, err: `found "-1", expected nonnegative INT`},
-		{s: `SELECT field FROM table1 JOIN table2 LIMIT -1`, err: `found "LIMIT", expected ON keyword`},
-		{s: `SELECT field FROM table1 JOIN table2`, err: `found "", expected ON keyword`},
+		{s: "SELECT a /* b */ FROM /* unterminated", err: `line 1, column 23: unterminated comment`},
+		{s: "SELECT a FROM t WHERE b = 'x", err: `line 1, column 27: unterminated string`},
+		{s: `SELECT a FROM t WHERE b = E'\u12'`, err: `line 1, column 27: invalid unicode escape \u12'`},
+		{s: `SELECT "a FROM t`, err: `line 1, column 8: unterminated quoted identifier`},
+		{s: `SELECT "" FROM t`, err: `line 1, column 8: zero-length quoted identifier`},
+		{s: "SELECT `a` FROM t", err: "line 1, column 8: found \"`\", expected field"},
+		{s: `foo`, err: `line 1, column 1: found "foo", expected SELECT`},
+		{s: `SELECT !`, err: `line 1, column 8: found "!", expected field`},
+		{s: `SELECT field xxx yyy`, err: `line 1, column 18: found "yyy", expected FROM`},
+		{s: `SELECT field AS FROM my_table`, err: `line 1, column 17: found "FROM", expected alias`},
+		{s: `SELECT field AS t.x FROM my_table`, err: `line 1, column 18: found ".", expected FROM`},
+		{s: `SELECT a.b.c.d.e FROM t`, err: `line 1, column 16: improper qualified name (too many dotted names): a.b.c.d.e`},
+		{s: `SELECT a FROM c.s.t.x`, err: `line 1, column 21: improper qualified name (too many dotted names): c.s.t.x`},
+		{s: `SELECT t., a FROM t`, err: `line 1, column 10: found ",", expected name`},
+		{s: `SELECT a FROM t.*`, err: `line 1, column 17: found "*", expected name`},
+		{s: `SELECT SUM(*) FROM table`, err: `line 1, column 12: found "*", expected SUM argument`},
+		{s: `SELECT COUNT(field FROM table`, err: `line 1, column 20: found "FROM", expected )`},
+		{s: `SELECT EXTRACT(year, at) FROM t`, err: `line 1, column 20: found ",", expected FROM`},
+		{s: `SELECT EXTRACT(year FROM at, 1) FROM t`, err: `line 1, column 28: found ",", expected )`},
+		{s: `SELECT field FROM *`, err: `line 1, column 19: found "*", expected table name`},
+		{s: `SELECT field FROM table OFFSET 1`, err: `line 1, column 25: found "OFFSET", invalid after FROM <table>`},
+		{s: `SELECT a FROM t GROUP BY a HAVING COUNT(*) > 1 OFFSET 2`, err: `line 1, column 48: OFFSET can only be defined for statement with ORDER BY`},
+		{s: `SELECT field FROM table ORDER BY field OFFSET 1.5`, err: `line 1, column 47: found "1.5", expected INT offset value`},
+		{s: `SELECT field FROM table ORDER BY field OFFSET -1`, err: `line 1, column 48: found "-1", expected nonnegative INT`},
+		{s: `SELECT field FROM table LIMIT -1`, err: `line 1, column 32: found "-1", expected nonnegative INT`},
+		{s: `SELECT field FROM table1 JOIN table2 LIMIT -1`, err: `line 1, column 38: found "LIMIT", expected ON keyword`},
+		{s: `SELECT field FROM table1 JOIN table2`, err: `line 1, column 37: found "", expected ON keyword`},
+		{s: `SELECT field FROM table WHERE (a = 1 OR b = 2`, err: `line 1, column 46: found "", expected )`},
+		{s: `SELECT field FROM table WHERE a = AND b = 2`, err: `line 1, column 35: found "AND", expected expression`},
+		{s: `SELECT field FROM table LIMIT -a`, err: `line 1, column 32: found "-", expected INT offset value`},
+		{s: `SELECT field FROM table WHERE a IS 1`, err: `line 1, column 36: found "1", expected NULL`},
+		{s: `SELECT field FROM table WHERE a IN 1`, err: `line 1, column 36: found "1", expected (`},
+		{s: `SELECT field FROM table WHERE a IN (1 2)`, err: `line 1, column 39: found "2", expected , or )`},
+		{s: `SELECT field FROM table WHERE a BETWEEN 1 OR 2`, err: `line 1, column 43: found "OR", expected AND`},
+		{s: `SELECT field FROM table WHERE a NOT = 1`, err: `line 1, column 37: found "=", expected IN, BETWEEN or LIKE`},
+		{s: `SELECT f(a FROM table`, err: `line 1, column 12: found "FROM", expected , or )`},
+		{s: `SELECT CAST a FROM table`, err: `line 1, column 13: found "a", expected (`},
+		{s: `SELECT CAST(a integer) FROM table`, err: `line 1, column 15: found "integer", expected AS`},
+		{s: `SELECT CAST(a AS integer FROM table`, err: `line 1, column 26: found "FROM", expected )`},
+		{s: `SELECT a::number FROM table`, err: `line 1, column 11: found "number", expected type`},
+		{s: `SELECT a::NULL FROM table`, err: `line 1, column 11: found "NULL", expected type`},
+		{s: `SELECT f(a,) FROM table`, err: `line 1, column 12: found ")", expected expression`},
+		{s: `SELECT CASE a END FROM table`, err: `line 1, column 15: found "END", expected WHEN`},
+		{s: `SELECT CASE WHEN a 1 END FROM table`, err: `line 1, column 20: found "1", expected THEN`},
+		{s: `SELECT CASE WHEN a THEN 1 ELSE 2 WHEN b THEN 3 END FROM table`, err: `line 1, column 34: found "WHEN", expected END`},
+		{s: `SELECT CASE WHEN a THEN 1 FROM table`, err: `line 1, column 27: found "FROM", expected END`},
+		{s: `SELECT field FROM table WHERE a IS NOT`, err: `line 1, column 39: found "", expected NULL`},
 	}
 
 	for i, tt := range tests {
@@ -262,13 +773,132 @@ FROM my_table`,
 			stmt, err := sql.NewParser(strings.NewReader(tt.s)).Parse()
 			if !reflect.DeepEqual(tt.err, errstring(err)) {
 				t.Errorf("%d. %q: error mismatch:\n  exp=%s\n  got=%s\n\n", i, tt.s, tt.err, err)
-			} else if tt.err == "" && !reflect.DeepEqual(tt.stmt, stmt) {
+			} else if tt.err == "" && !reflect.DeepEqual(tt.stmt, clearPos(stmt)) {
 				t.Errorf("%d. %q\n\nstmt mismatch:\n\nexp=%#v\n\ngot=%#v\n\n", i, tt.s, tt.stmt, stmt)
 			}
 		})
 	}
 }
 
+func TestParser_AllowBackticks(t *testing.T) {
+	p := sql.NewParser(strings.NewReader("SELECT `first name` FROM `order`"))
+	p.AllowBackticks()
+	stmt, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := &sql.SelectStmt{
+		Fields: []sql.Expr{&sql.QualifiedName{Column: "first name"}},
+		From:   sql.FromClause{TableName: &sql.QualifiedName{Table: "order"}},
+	}
+	if !reflect.DeepEqual(want, clearPos(stmt)) {
+		t.Errorf("Parse() = %#v, want %#v", stmt, want)
+	}
+}
+
+func TestQualifiedName_String(t *testing.T) {
+	tests := []struct {
+		name *sql.QualifiedName
+		want string
+	}{
+		{name: &sql.QualifiedName{Table: "t", Column: "a"}, want: `t.a`},
+		{name: &sql.QualifiedName{Schema: "my", Table: "table"}, want: `my.table`},
+		{name: &sql.QualifiedName{Table: "order", Column: "a"}, want: `"order".a`},
+		{name: &sql.QualifiedName{Table: "my.table"}, want: `"my.table"`},
+		{name: &sql.QualifiedName{Catalog: "db", Schema: "s", Table: "My Table", Column: `a"b`}, want: `db.s."My Table"."a""b"`},
+		{name: &sql.QualifiedName{Table: "t", Column: "*"}, want: `t.*`},
+		{name: &sql.QualifiedName{Column: "_a1"}, want: `"_a1"`},
+		{name: &sql.QualifiedName{Column: "Name_1"}, want: `Name_1`},
+	}
+	for _, tt := range tests {
+		if got := tt.name.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestParser_Positions(t *testing.T) {
+	stmt, err := sql.NewParser(strings.NewReader("SELECT a, -b AS c\nFROM t\nWHERE x + 1 > CAST(y AS INT)\n\tAND z IN (1, 2)")).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	pos := func(offset, line, column int) sql.Pos { return sql.Pos{Offset: offset, Line: line, Column: column} }
+
+	and := stmt.Where.Predicate.(*sql.BinaryExpr)
+	gt := and.LHS.(*sql.BinaryExpr)
+	alias := stmt.Fields[1].(*sql.AliasExpr)
+	tests := []struct {
+		name string
+		got  sql.Pos
+		want sql.Pos
+	}{
+		{name: "statement", got: stmt.Pos, want: pos(0, 1, 1)},
+		{name: "field", got: stmt.Fields[0].Position(), want: pos(7, 1, 8)},
+		{name: "alias", got: alias.Position(), want: pos(10, 1, 11)},
+		{name: "alias name", got: alias.Alias.Pos, want: pos(16, 1, 17)},
+		{name: "from", got: stmt.From.Pos, want: pos(18, 2, 1)},
+		{name: "table", got: stmt.From.TableName.Position(), want: pos(23, 2, 6)},
+		{name: "where", got: stmt.Where.Pos, want: pos(25, 3, 1)},
+		{name: "conjunction", got: and.Position(), want: pos(31, 3, 7)},
+		{name: "comparison", got: gt.Position(), want: pos(31, 3, 7)},
+		{name: "cast", got: gt.RHS.Position(), want: pos(39, 3, 15)},
+		{name: "in", got: and.RHS.Position(), want: pos(59, 4, 6)},
+		{name: "in value", got: and.RHS.(*sql.InExpr).List[1].Position(), want: pos(68, 4, 15)},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s position = %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestParser_SyntaxError(t *testing.T) {
+	_, err := sql.NewParser(strings.NewReader("SELECT a,\n\tb c d\nFROM t")).Parse()
+	var serr *sql.SyntaxError
+	if !errors.As(err, &serr) {
+		t.Fatalf("Parse() error = %v, want a *SyntaxError", err)
+	}
+	if want := (sql.Pos{Offset: 15, Line: 2, Column: 6}); serr.Pos != want {
+		t.Errorf("SyntaxError.Pos = %+v, want %+v", serr.Pos, want)
+	}
+	if want := `found "d", expected FROM`; serr.Msg != want {
+		t.Errorf("SyntaxError.Msg = %q, want %q", serr.Msg, want)
+	}
+	if want := "\tb c d\n\t    ^"; serr.Snippet != want {
+		t.Errorf("SyntaxError.Snippet = %q, want %q", serr.Snippet, want)
+	}
+	if want := `line 2, column 6: found "d", expected FROM`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+// clearPos zeroes the positions of the nodes of a syntax tree, so that it can be compared to a tree built by hand.
+func clearPos(stmt *sql.SelectStmt) *sql.SelectStmt {
+	var clear func(v reflect.Value)
+	clear = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if !v.IsNil() {
+				clear(v.Elem())
+			}
+		case reflect.Slice:
+			for i := 0; i < v.Len(); i++ {
+				clear(v.Index(i))
+			}
+		case reflect.Struct:
+			if v.Type() == reflect.TypeOf(sql.Pos{}) {
+				v.Set(reflect.Zero(v.Type()))
+				return
+			}
+			for i := 0; i < v.NumField(); i++ {
+				clear(v.Field(i))
+			}
+		}
+	}
+	clear(reflect.ValueOf(stmt))
+	return stmt
+}
+
 // errstring returns the string representation of an error.
 func errstring(err error) string {
 	if err != nil {
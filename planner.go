@@ -12,27 +12,84 @@ type PlanNode interface {
 
 // ProjectionNode represents the columns to keep after a stage.
 type ProjectionNode struct {
-	Columns []Ident
+	Columns []Expr
 	From    PlanNode
 }
 
 // TableScanNode is a full table scan
+// When the relation is aliased, its columns are qualified by the alias instead of the relation name.
+// Catalog and Schema qualify the name of the relation when they are written in the FROM clause.
 type TableScanNode struct {
+	Catalog      string
 	Schema       string
 	RelationName string
+	Alias        string
+}
+
+// relation returns the qualified name the relation is looked up by in the catalog.
+func (n *TableScanNode) relation() *QualifiedName {
+	return &QualifiedName{Catalog: n.Catalog, Schema: n.Schema, Table: n.RelationName}
+}
+
+// name returns the name qualifying the columns of the scanned relation.
+func (n *TableScanNode) name() string {
+	if n.Alias != "" {
+		return n.Alias
+	}
+	return n.RelationName
 }
 
 // SortNode is an in memory sort of the working set
 type SortNode struct {
-	Keys   []string
+	Keys   []Expr
 	Method string
 	From   PlanNode
 }
 
-// NestedLoopNode is a join without indexes
+// NestedLoopNode is a join without indexes, the inner plan is scanned for each row of the outer plan.
 type NestedLoopNode struct {
-	JoinType string
-	From     PlanNode
+	Kind      JoinKind
+	Criterion Expr
+	Outer     PlanNode
+	Inner     PlanNode
+}
+
+// HashJoinNode is an equi-join, a hash table is built on the smaller input and probed with the rows of the other.
+// OuterKey and InnerKey are the operands of the criterion evaluated on the outer and inner rows respectively.
+type HashJoinNode struct {
+	Kind      JoinKind
+	Criterion Expr
+	OuterKey  Expr
+	InnerKey  Expr
+	Outer     PlanNode
+	Inner     PlanNode
+}
+
+// MergeJoinNode is an equi-join of two inputs sorted on their join keys, only the rows sharing
+// the same key are kept in memory.
+// OuterKey and InnerKey are the operands of the criterion evaluated on the outer and inner rows respectively.
+type MergeJoinNode struct {
+	Kind      JoinKind
+	Criterion Expr
+	OuterKey  Expr
+	InnerKey  Expr
+	Outer     PlanNode
+	Inner     PlanNode
+}
+
+// HashAggregateNode groups the rows in a hash table and computes the aggregates of each group.
+type HashAggregateNode struct {
+	Groups     []*QualifiedName
+	Aggregates []*AggregateExpr
+	From       PlanNode
+}
+
+// GroupAggregateNode computes the aggregates of an input sorted on the groups, one group at a time.
+// Without groups, the whole input is aggregated in a single row.
+type GroupAggregateNode struct {
+	Groups     []*QualifiedName
+	Aggregates []*AggregateExpr
+	From       PlanNode
 }
 
 // LimitNode is a limit to the number of rows returned.
@@ -47,41 +104,147 @@ type OffsetNode struct {
 	From  PlanNode
 }
 
+// HashDistinctNode removes the duplicated rows of the set by hashing the rows already returned.
+type HashDistinctNode struct {
+	From PlanNode
+}
+
+// SortedDistinctNode removes the duplicated rows of an input sorted on all of its columns,
+// duplicates are then next to each other.
+type SortedDistinctNode struct {
+	From PlanNode
+}
+
 // FilterNode is a filter based on conditions in the where clause.
 type FilterNode struct {
 	Filter Expr
 	From   PlanNode
 }
 
-func (*ProjectionNode) planNode() {}
-func (*TableScanNode) planNode()  {}
-func (*SortNode) planNode()       {}
-func (*NestedLoopNode) planNode() {}
-func (*LimitNode) planNode()      {}
-func (*OffsetNode) planNode()     {}
-func (*FilterNode) planNode()     {}
+func (*ProjectionNode) planNode()     {}
+func (*TableScanNode) planNode()      {}
+func (*SortNode) planNode()           {}
+func (*NestedLoopNode) planNode()     {}
+func (*HashJoinNode) planNode()       {}
+func (*MergeJoinNode) planNode()      {}
+func (*HashAggregateNode) planNode()  {}
+func (*GroupAggregateNode) planNode() {}
+func (*LimitNode) planNode()          {}
+func (*OffsetNode) planNode()         {}
+func (*HashDistinctNode) planNode()   {}
+func (*SortedDistinctNode) planNode() {}
+func (*FilterNode) planNode()         {}
 
 type Catalog interface {
-	GetRelation(string) (Relation, error)
-	// HasColumn checks if a column of this name exists in any relations of the catalog.
-	HasColumn(string) bool
+	// GetRelation finds a relation by its name, its Catalog and Schema are empty when they are not written.
+	GetRelation(*QualifiedName) (Relation, error)
 }
 
+// NodeSchema holds the relations visible from a node of the plan, by the name they are referred to.
+// An aliased relation is only visible through its alias.
 type NodeSchema struct {
 	Relations map[string]Relation
+	// Names holds the names the relations are referred to, as qualified in the FROM clause.
+	Names map[string]*QualifiedName
+}
+
+// with returns a copy of the schema where the relation is also visible by its name.
+func (s NodeSchema) with(name *QualifiedName, r Relation) NodeSchema {
+	schema := NodeSchema{
+		Relations: map[string]Relation{name.Table: r},
+		Names:     map[string]*QualifiedName{name.Table: name},
+	}
+	for table, r := range s.Relations {
+		schema.Relations[table] = r
+		schema.Names[table] = s.Names[table]
+	}
+	return schema
+}
+
+// HasColumn checks if a column exists in the relations of the schema and is not ambiguous.
+// The name of the column can be qualified by the name of its relation.
+func (s NodeSchema) HasColumn(name *QualifiedName) bool {
+	_, _, err := s.lookupColumn(name)
+	return err == nil
+}
+
+// lookupColumn finds a column and the name of its relation in the schema.
+// A column which is not qualified by the name of its relation must belong to a single relation.
+func (s NodeSchema) lookupColumn(name *QualifiedName) (string, Column, error) {
+	var table string
+	var column Column
+	for visible, r := range s.Relations {
+		if name.Table != "" && !s.qualifies(name, visible) {
+			continue
+		}
+		c, ok := r.column(name.Column)
+		if !ok {
+			continue
+		}
+		if table != "" {
+			return "", Column{}, fmt.Errorf("ambiguous column \"%s\"", name)
+		}
+		table, column = visible, c
+	}
+	if table == "" {
+		return "", Column{}, fmt.Errorf("unknown column \"%s\"", name)
+	}
+	return table, column, nil
+}
+
+// hasRelation checks if the qualifiers of a column name designate a relation of the schema.
+func (s NodeSchema) hasRelation(name *QualifiedName) bool {
+	for visible := range s.Relations {
+		if s.qualifies(name, visible) {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifies checks if the qualifiers of a column name designate the relation visible by the given name.
+// The schema and catalog of a column can only be written when the relation is qualified by them in the FROM clause.
+func (s NodeSchema) qualifies(name *QualifiedName, visible string) bool {
+	if name.Table != visible {
+		return false
+	}
+	n := s.Names[visible]
+	return (name.Schema == "" || name.Schema == n.Schema) && (name.Catalog == "" || name.Catalog == n.Catalog)
+}
+
+// columns lists the columns of the relations of the schema, qualified by the name of their relation.
+func (s NodeSchema) columns() []ResultColumn {
+	var cols []ResultColumn
+	for table, r := range s.Relations {
+		for _, c := range r.Columns {
+			cols = append(cols, ResultColumn{Table: table, Name: c.Name, Type: c.Type})
+		}
+	}
+	return cols
 }
 
 type Relation struct {
 	Name     string
 	Location interface{}
-	Schema   map[string]Column
+	// Columns lists the columns of the relation in the order of its rows.
+	Columns []Column
+	// SortedBy lists the columns the rows of the relation are stored sorted by, if any.
+	SortedBy []string
 }
 
 func (r Relation) HasColumn(name string) bool {
-	if _, ok := r.Schema[name]; ok {
-		return true
+	_, ok := r.column(name)
+	return ok
+}
+
+// column finds a column of the relation by its name.
+func (r Relation) column(name string) (Column, bool) {
+	for _, c := range r.Columns {
+		if c.Name == name {
+			return c, true
+		}
 	}
-	return false
+	return Column{}, false
 }
 
 // Column is the metadata about a relation's column
@@ -120,7 +283,8 @@ func planSelect(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
 		planFn = planLimit
 	} else if stmt.Offset != nil {
 		planFn = planOffset
-
+	} else if stmt.Distinct {
+		planFn = planDistinct
 	} else if stmt.OrderBy != nil {
 		planFn = planSort
 	} else {
@@ -134,29 +298,18 @@ func planSelect(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
 	return plan, nil
 }
 
-func planFilter(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
-	if err := validateExpr(catalog, stmt.Where.Predicate); err != nil {
+func planFilter(schema NodeSchema, stmt *SelectStmt, from PlanNode) (PlanNode, error) {
+	if err := validatePredicate(schema, stmt.Where.Predicate); err != nil {
 		return nil, err
-
+	}
+	if hasAggregate(stmt.Where.Predicate) {
+		return nil, errors.New("aggregate functions are not allowed in WHERE")
 	}
 	plan := FilterNode{
 		Filter: stmt.Where.Predicate,
+		From:   from,
 	}
 
-	n, ok := stmt.From.TableName.(*Ident)
-	if !ok {
-		return nil, errors.New("invalid expression in FROM clause")
-	}
-	relation, err := catalog.GetRelation(n.Name)
-	if err != nil {
-		return nil, err
-	}
-	from, err := planTableScan(relation)
-	if err != nil {
-		return nil, err
-	}
-	plan.From = from
-
 	return &plan, nil
 }
 
@@ -165,19 +318,15 @@ func planProjection(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
 		return nil, errors.New("invalid statement: no columns in select")
 	}
 
-	n, ok := stmt.From.TableName.(*Ident)
-	if !ok {
-		return nil, errors.New("invalid expression in FROM clause")
-	}
-	relation, err := catalog.GetRelation(n.Name)
+	from, schema, err := planFrom(catalog, stmt.From)
 	if err != nil {
 		return nil, err
 	}
 
-	var cols []Ident
+	var cols []Expr
 	for _, field := range stmt.Fields {
-		if !relation.HasColumn(field.Name) {
-			return nil, fmt.Errorf("unknown column in statement: %s", field.Name)
+		if err := validateField(schema, field); err != nil {
+			return nil, err
 		}
 		cols = append(cols, field)
 	}
@@ -185,14 +334,14 @@ func planProjection(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
 		Columns: cols,
 	}
 
-	var from PlanNode
 	if stmt.Where != nil {
-		from, err = planFilter(catalog, stmt)
+		from, err = planFilter(schema, stmt, from)
 		if err != nil {
 			return nil, err
 		}
-	} else {
-		from, err = planTableScan(relation)
+	}
+	if isAggregateQuery(stmt) {
+		from, err = planAggregate(schema, stmt, from)
 		if err != nil {
 			return nil, err
 		}
@@ -202,6 +351,311 @@ func planProjection(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
 	return &plan, nil
 }
 
+// validateField ensures all identifiers of a field of the select list exist in the schema.
+func validateField(schema NodeSchema, field Expr) error {
+	switch f := field.(type) {
+	case *QualifiedName:
+		if f.Column != "*" {
+			return validateExpr(schema, f)
+		}
+		if f.Table != "" && !schema.hasRelation(f) {
+			return fmt.Errorf("unknown table in statement: %s", f)
+		}
+		return nil
+	case *AliasExpr:
+		return validateField(schema, f.Expr)
+	default:
+		return validateExpr(schema, field)
+	}
+}
+
+func isAggregateQuery(stmt *SelectStmt) bool {
+	if stmt.GroupBy != nil || stmt.Having != nil {
+		return true
+	}
+	for _, field := range stmt.Fields {
+		if hasAggregate(field) {
+			return true
+		}
+	}
+	return false
+}
+
+// planAggregate groups the rows by the columns of the GROUP BY clause and computes the aggregates of the select list
+// and of the HAVING clause, which filters the groups.
+// Every column of the select list and of the HAVING clause must either be grouped or aggregated.
+// Inputs already sorted on the groups are aggregated one group at a time, otherwise the groups are hashed.
+func planAggregate(schema NodeSchema, stmt *SelectStmt, from PlanNode) (PlanNode, error) {
+	var groups []*QualifiedName
+	if stmt.GroupBy != nil {
+		for _, g := range stmt.GroupBy.Fields {
+			if _, _, err := schema.lookupColumn(g); err != nil {
+				return nil, fmt.Errorf("invalid GROUP BY: %w", err)
+			}
+			groups = append(groups, g)
+		}
+	}
+
+	var aggregates []*AggregateExpr
+	var err error
+	for _, field := range stmt.Fields {
+		if aggregates, err = collectAggregates(schema, groups, field, aggregates); err != nil {
+			return nil, err
+		}
+	}
+	if stmt.Having != nil {
+		if err := validatePredicate(schema, stmt.Having.Predicate); err != nil {
+			return nil, err
+		}
+		if aggregates, err = collectAggregates(schema, groups, stmt.Having.Predicate, aggregates); err != nil {
+			return nil, err
+		}
+	}
+
+	var plan PlanNode
+	if len(groups) == 0 || isSortedOn(schema, from, groups...) {
+		plan = &GroupAggregateNode{
+			Groups:     groups,
+			Aggregates: aggregates,
+			From:       from,
+		}
+	} else {
+		plan = &HashAggregateNode{
+			Groups:     groups,
+			Aggregates: aggregates,
+			From:       from,
+		}
+	}
+
+	if stmt.Having != nil {
+		plan = &FilterNode{
+			Filter: stmt.Having.Predicate,
+			From:   plan,
+		}
+	}
+	return plan, nil
+}
+
+// collectAggregates adds the aggregates of the expression to the list,
+// and ensures the columns used outside of the aggregates are grouped.
+func collectAggregates(schema NodeSchema, groups []*QualifiedName, expr Expr, aggregates []*AggregateExpr) ([]*AggregateExpr, error) {
+	var err error
+	Inspect(expr, func(e Expr) bool {
+		if err != nil {
+			return false
+		}
+		switch n := e.(type) {
+		case *AggregateExpr:
+			aggregates = appendAggregate(aggregates, n)
+			return false
+		case *QualifiedName:
+			if !isGrouped(schema, groups, n) {
+				err = fmt.Errorf("column \"%s\" must appear in the GROUP BY clause or be used in an aggregate function", n)
+			}
+		}
+		return true
+	})
+	return aggregates, err
+}
+
+// appendAggregate adds an aggregate to the list, unless the same aggregate is already computed.
+func appendAggregate(aggregates []*AggregateExpr, a *AggregateExpr) []*AggregateExpr {
+	for _, b := range aggregates {
+		if a.String() == b.String() {
+			return aggregates
+		}
+	}
+	return append(aggregates, a)
+}
+
+// isGrouped checks if the column is one of the groups.
+func isGrouped(schema NodeSchema, groups []*QualifiedName, name *QualifiedName) bool {
+	table, c, err := schema.lookupColumn(name)
+	if err != nil {
+		return false
+	}
+	for _, g := range groups {
+		if gt, gc, err := schema.lookupColumn(g); err == nil && gt == table && gc.Name == c.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// planFrom plans the scan of the table of the FROM clause and its joins.
+// It returns the schema of the relations visible from the plan.
+func planFrom(catalog Catalog, from FromClause) (PlanNode, NodeSchema, error) {
+	var schema NodeSchema
+
+	relation, name, visible, err := getRelation(catalog, from.TableName)
+	if err != nil {
+		return nil, schema, err
+	}
+	schema = schema.with(visible, relation)
+
+	plan, err := planTableScan(relation, name, visible)
+	if err != nil {
+		return nil, schema, err
+	}
+
+	for join := from.Join; join != nil; join = join.Join {
+		relation, name, visible, err := getRelation(catalog, join.TableName)
+		if err != nil {
+			return nil, schema, err
+		}
+		if _, ok := schema.Relations[visible.Table]; ok {
+			return nil, schema, fmt.Errorf("table \"%s\" specified more than once", visible.Table)
+		}
+		inner, err := planTableScan(relation, name, visible)
+		if err != nil {
+			return nil, schema, err
+		}
+
+		plan, err = planJoin(schema, visible, relation, join, plan, inner)
+		if err != nil {
+			return nil, schema, err
+		}
+		schema = schema.with(visible, relation)
+	}
+
+	return plan, schema, nil
+}
+
+// planJoin joins the relation to the plan of the relations already in the outer schema.
+// Equi-joins are planned as merge joins when one of the inputs is already sorted on its key,
+// otherwise as hash joins. Other criteria fall back to a nested loop.
+func planJoin(outer NodeSchema, name *QualifiedName, relation Relation, join *JoinSubClause, outerPlan, innerPlan PlanNode) (PlanNode, error) {
+	schema := outer.with(name, relation)
+
+	criterion := join.Criterion
+	if err := validatePredicate(schema, criterion); err != nil {
+		return nil, err
+	}
+	if hasAggregate(criterion) {
+		return nil, errors.New("aggregate functions are not allowed in ON")
+	}
+
+	inner := NodeSchema{}.with(name, relation)
+	if outerKey, innerKey, ok := equiJoinKeys(outer, inner, criterion); ok {
+		outerSorted := isSortedOn(outer, outerPlan, outerKey)
+		innerSorted := isSortedOn(inner, innerPlan, innerKey)
+		if outerSorted || innerSorted {
+			if !outerSorted {
+				outerPlan = &SortNode{Keys: []Expr{outerKey}, From: outerPlan}
+			}
+			if !innerSorted {
+				innerPlan = &SortNode{Keys: []Expr{innerKey}, From: innerPlan}
+			}
+			return &MergeJoinNode{
+				Kind:      join.Kind,
+				Criterion: criterion,
+				OuterKey:  outerKey,
+				InnerKey:  innerKey,
+				Outer:     outerPlan,
+				Inner:     innerPlan,
+			}, nil
+		}
+
+		return &HashJoinNode{
+			Kind:      join.Kind,
+			Criterion: criterion,
+			OuterKey:  outerKey,
+			InnerKey:  innerKey,
+			Outer:     outerPlan,
+			Inner:     innerPlan,
+		}, nil
+	}
+
+	return &NestedLoopNode{
+		Kind:      join.Kind,
+		Criterion: criterion,
+		Outer:     outerPlan,
+		Inner:     innerPlan,
+	}, nil
+}
+
+// equiJoinKeys checks if the criterion is an equality between a column of the outer schema
+// and a column of the inner one, and returns them in this order.
+func equiJoinKeys(outer, inner NodeSchema, expr Expr) (*QualifiedName, *QualifiedName, bool) {
+	criterion, ok := expr.(*BinaryExpr)
+	if !ok || criterion.Op != EQ {
+		return nil, nil, false
+	}
+	lhs, ok := criterion.LHS.(*QualifiedName)
+	if !ok {
+		return nil, nil, false
+	}
+	rhs, ok := criterion.RHS.(*QualifiedName)
+	if !ok {
+		return nil, nil, false
+	}
+
+	inOuter := func(n *QualifiedName) bool { return outer.HasColumn(n) && !inner.HasColumn(n) }
+	inInner := func(n *QualifiedName) bool { return inner.HasColumn(n) && !outer.HasColumn(n) }
+	switch {
+	case inOuter(lhs) && inInner(rhs):
+		return lhs, rhs, true
+	case inInner(lhs) && inOuter(rhs):
+		return rhs, lhs, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// isSortedOn checks if the rows produced by the plan are known to be sorted on the columns.
+func isSortedOn(schema NodeSchema, plan PlanNode, keys ...*QualifiedName) bool {
+	switch n := plan.(type) {
+	case *TableScanNode:
+		r, ok := schema.Relations[n.name()]
+		if !ok || len(r.SortedBy) < len(keys) {
+			return false
+		}
+		for i, key := range keys {
+			if (key.Table != "" && key.Table != n.name()) || r.SortedBy[i] != key.Column {
+				return false
+			}
+		}
+		return true
+	case *SortNode:
+		if len(n.Keys) < len(keys) {
+			return false
+		}
+		for i, key := range keys {
+			if k, ok := n.Keys[i].(*QualifiedName); !ok || k.String() != key.String() {
+				return false
+			}
+		}
+		return true
+	case *FilterNode:
+		return isSortedOn(schema, n.From, keys...)
+	default:
+		return false
+	}
+}
+
+// getRelation finds the relation named in the FROM clause. It returns its name and the name it is referred to by
+// in the statement, either its alias or its name.
+func getRelation(catalog Catalog, expr Expr) (Relation, *QualifiedName, *QualifiedName, error) {
+	var alias *QualifiedName
+	if a, ok := expr.(*AliasExpr); ok {
+		alias = &QualifiedName{Table: a.Alias.Name, Pos: a.Alias.Pos}
+		expr = a.Expr
+	}
+	n, ok := expr.(*QualifiedName)
+	if !ok {
+		return Relation{}, nil, nil, errors.New("invalid expression in FROM clause")
+	}
+
+	r, err := catalog.GetRelation(n)
+	if err != nil {
+		return Relation{}, nil, nil, err
+	}
+	if alias == nil {
+		alias = n
+	}
+	return r, n, alias, nil
+}
+
 func planLimit(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
 	plan := LimitNode{
 		Value: stmt.Limit.Value,
@@ -210,6 +664,8 @@ func planLimit(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
 	var planFn planFunc
 	if stmt.Offset != nil {
 		planFn = planOffset
+	} else if stmt.Distinct {
+		planFn = planDistinct
 	} else if stmt.OrderBy != nil {
 		planFn = planSort
 	} else {
@@ -231,7 +687,9 @@ func planOffset(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
 	}
 
 	var planFn planFunc
-	if stmt.OrderBy != nil {
+	if stmt.Distinct {
+		planFn = planDistinct
+	} else if stmt.OrderBy != nil {
 		planFn = planSort
 	} else {
 		planFn = planProjection
@@ -246,13 +704,60 @@ func planOffset(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
 	return &plan, nil
 }
 
-func planSort(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
-	var keys []string
-	for _, id := range stmt.OrderBy.Fields {
-		keys = append(keys, id.Name)
+// planDistinct removes the duplicates after the rows are sorted, so that they can be
+// deduplicated as they come when the ORDER BY clause covers the whole select list.
+func planDistinct(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
+	var planFn planFunc
+	if stmt.OrderBy != nil {
+		planFn = planSort
+	} else {
+		planFn = planProjection
+	}
+
+	from, err := planFn(catalog, stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := from.(*SortNode); ok {
+		sorted, err := coversColumns(catalog, s)
+		if err != nil {
+			return nil, err
+		}
+		if sorted {
+			return &SortedDistinctNode{From: from}, nil
+		}
 	}
+	return &HashDistinctNode{From: from}, nil
+}
+
+// coversColumns checks if the keys of the sort include every column of its rows.
+func coversColumns(catalog Catalog, n *SortNode) (bool, error) {
+	cols, err := planColumns(catalog, n, nil)
+	if err != nil {
+		return false, err
+	}
+	seen := make(map[int]bool, len(n.Keys))
+	for _, k := range n.Keys {
+		id, ok := k.(*QualifiedName)
+		if !ok {
+			continue
+		}
+		i, err := resolveColumn(cols, id)
+		if err != nil {
+			return false, err
+		}
+		seen[i] = true
+	}
+	return len(seen) == len(cols), nil
+}
+
+// planSort sorts the rows of the projection when all the keys are columns of the select list.
+// Otherwise the rows are sorted before they are projected, and the keys naming an alias of the select list
+// are replaced by the aliased expressions.
+func planSort(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
 	plan := SortNode{
-		Keys: keys,
+		Keys: stmt.OrderBy.Fields,
 	}
 
 	from, err := planProjection(catalog, stmt)
@@ -261,36 +766,99 @@ func planSort(catalog Catalog, stmt *SelectStmt) (PlanNode, error) {
 	}
 	plan.From = from
 
-	return &plan, nil
+	cols, err := planColumns(catalog, from, nil)
+	if err != nil {
+		return nil, err
+	}
+	if keysResolve(plan.Keys, cols) {
+		return &plan, nil
+	}
+	if stmt.Distinct {
+		return nil, errors.New("for SELECT DISTINCT, ORDER BY expressions must appear in select list")
+	}
+
+	projection := from.(*ProjectionNode)
+	plan.Keys = unaliasKeys(stmt.OrderBy.Fields, stmt.Fields)
+	plan.From = projection.From
+	projection.From = &plan
+
+	// The keys are evaluated against the rows of the input of the projection.
+	if _, err := planColumns(catalog, &plan, nil); err != nil {
+		return nil, err
+	}
+
+	return projection, nil
 }
 
-func planTableScan(r Relation) (PlanNode, error) {
-	return &TableScanNode{
-		RelationName: r.Name,
-	}, nil
+// keysResolve checks if all the keys can be evaluated against the columns.
+func keysResolve(keys []Expr, cols []ResultColumn) bool {
+	for _, k := range keys {
+		if _, err := checkExpr(k, cols, nil); err != nil {
+			return false
+		}
+	}
+	return true
 }
 
-// validateExpr walks through an expression and ensure all identifiers present exist in the catalog.
-func validateExpr(catalog Catalog, expr Expr) error {
-	switch e := expr.(type) {
-	case *Ident:
-		if catalog.HasColumn(e.Name) {
-			return nil
+// unaliasKeys replaces the keys naming an alias of the fields by the aliased expression.
+func unaliasKeys(keys []Expr, fields []Expr) []Expr {
+	aliases := make(map[string]Expr)
+	for _, f := range fields {
+		if a, ok := f.(*AliasExpr); ok {
+			aliases[a.Alias.Name] = a.Expr
 		}
-		return fmt.Errorf("unknown column, \"%s\" in statement", e.Name)
-	case *BasicLit:
-		return nil
-	case *UnaryExpr:
-		return validateExpr(catalog, e.X)
-	case *BinaryExpr:
-		var err error
-		if err = validateExpr(catalog, e.LHS); err != nil {
-			return err
-		} else if err = validateExpr(catalog, e.RHS); err != nil {
-			return err
+	}
+
+	out := make([]Expr, len(keys))
+	for i, k := range keys {
+		out[i] = k
+		if n, ok := k.(*QualifiedName); ok && n.Table == "" {
+			if expr, ok := aliases[n.Column]; ok {
+				out[i] = expr
+			}
 		}
-		return nil
-	default:
-		return fmt.Errorf("invalid expression")
 	}
+	return out
+}
+
+func planTableScan(r Relation, name, visible *QualifiedName) (PlanNode, error) {
+	plan := TableScanNode{
+		Catalog:      name.Catalog,
+		Schema:       name.Schema,
+		RelationName: r.Name,
+	}
+	if visible.Table != r.Name {
+		plan.Alias = visible.Table
+	}
+	return &plan, nil
+}
+
+// validateExpr ensures all identifiers of an expression exist in the schema,
+// and that its operations are valid for the types of their operands.
+func validateExpr(schema NodeSchema, expr Expr) error {
+	var err error
+	Inspect(expr, func(e Expr) bool {
+		switch n := e.(type) {
+		case *AggregateExpr:
+			if n.Arg != nil && hasAggregate(n.Arg) {
+				err = errors.New("aggregate function calls cannot be nested")
+			}
+		case *QualifiedName:
+			_, _, err = schema.lookupColumn(n)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = checkExpr(expr, schema.columns(), nil)
+	return err
+}
+
+// validatePredicate ensures an expression is valid and computes a BOOLEAN.
+func validatePredicate(schema NodeSchema, expr Expr) error {
+	if err := validateExpr(schema, expr); err != nil {
+		return err
+	}
+	return checkPredicate(expr, schema.columns(), nil)
 }
@@ -3,6 +3,7 @@ package sql_test
 import (
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	sql "github.com/ndilsou/go-rdbms-playground"
@@ -18,9 +19,9 @@ func TestPlanner_Plan(t *testing.T) {
 		{
 			name: "no relation",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "first_name"}, {Name: "last_name"}, {Name: "age"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "first_name"}, &sql.QualifiedName{Column: "last_name"}, &sql.QualifiedName{Column: "age"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "my_table"},
+					TableName: &sql.QualifiedName{Table: "my_table"},
 				},
 			},
 			wantErr: true,
@@ -28,9 +29,9 @@ func TestPlanner_Plan(t *testing.T) {
 		{
 			name: "no column",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{},
+				Fields: []sql.Expr{},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 				},
 			},
 			wantErr: true,
@@ -38,13 +39,13 @@ func TestPlanner_Plan(t *testing.T) {
 		{
 			name: "simple plan",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 				},
 			},
 			want: &sql.ProjectionNode{
-				Columns: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Columns: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: &sql.TableScanNode{
 					RelationName: "t1",
 				},
@@ -53,23 +54,23 @@ func TestPlanner_Plan(t *testing.T) {
 		{
 			name: "plan with where",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 				},
 				Where: &sql.WhereClause{
 					Predicate: &sql.BinaryExpr{
-						LHS: &sql.Ident{Name: "a"},
+						LHS: &sql.QualifiedName{Column: "a"},
 						Op:  sql.EQ,
 						RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
 					},
 				},
 			},
 			want: &sql.ProjectionNode{
-				Columns: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Columns: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: &sql.FilterNode{
 					Filter: &sql.BinaryExpr{
-						LHS: &sql.Ident{Name: "a"},
+						LHS: &sql.QualifiedName{Column: "a"},
 						Op:  sql.EQ,
 						RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
 					},
@@ -82,9 +83,9 @@ func TestPlanner_Plan(t *testing.T) {
 		{
 			name: "plan with limit",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 				},
 				Limit: &sql.LimitClause{
 					Value: 10,
@@ -93,7 +94,96 @@ func TestPlanner_Plan(t *testing.T) {
 			want: &sql.LimitNode{
 				Value: 10,
 				From: &sql.ProjectionNode{
-					Columns: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+					Columns: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
+					From: &sql.TableScanNode{
+						RelationName: "t1",
+					},
+				},
+			},
+		},
+		{
+			name: "plan with distinct",
+			stmt: &sql.SelectStmt{
+				Distinct: true,
+				Fields:   []sql.Expr{&sql.QualifiedName{Column: "c"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				Limit:   &sql.LimitClause{Value: 2},
+				OrderBy: &sql.OrderByClause{Fields: []sql.Expr{&sql.QualifiedName{Column: "c"}}},
+			},
+			want: &sql.LimitNode{
+				Value: 2,
+				From: &sql.SortedDistinctNode{
+					From: &sql.SortNode{
+						Keys: []sql.Expr{&sql.QualifiedName{Column: "c"}},
+						From: &sql.ProjectionNode{
+							Columns: []sql.Expr{&sql.QualifiedName{Column: "c"}},
+							From: &sql.TableScanNode{
+								RelationName: "t1",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "plan with order by a column not selected",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.AliasExpr{Expr: &sql.QualifiedName{Column: "a"}, Alias: sql.Ident{Name: "k"}},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				OrderBy: &sql.OrderByClause{Fields: []sql.Expr{&sql.QualifiedName{Column: "c"}, &sql.QualifiedName{Column: "k"}}},
+			},
+			want: &sql.ProjectionNode{
+				Columns: []sql.Expr{
+					&sql.AliasExpr{Expr: &sql.QualifiedName{Column: "a"}, Alias: sql.Ident{Name: "k"}},
+				},
+				From: &sql.SortNode{
+					Keys: []sql.Expr{&sql.QualifiedName{Column: "c"}, &sql.QualifiedName{Column: "a"}},
+					From: &sql.TableScanNode{
+						RelationName: "t1",
+					},
+				},
+			},
+		},
+		{
+			name: "plan with distinct not sorted on all columns",
+			stmt: &sql.SelectStmt{
+				Distinct: true,
+				Fields:   []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "c"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				OrderBy: &sql.OrderByClause{Fields: []sql.Expr{&sql.QualifiedName{Column: "c"}}},
+			},
+			want: &sql.HashDistinctNode{
+				From: &sql.SortNode{
+					Keys: []sql.Expr{&sql.QualifiedName{Column: "c"}},
+					From: &sql.ProjectionNode{
+						Columns: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "c"}},
+						From: &sql.TableScanNode{
+							RelationName: "t1",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "plan with distinct without order by",
+			stmt: &sql.SelectStmt{
+				Distinct: true,
+				Fields:   []sql.Expr{&sql.QualifiedName{Column: "c"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+			},
+			want: &sql.HashDistinctNode{
+				From: &sql.ProjectionNode{
+					Columns: []sql.Expr{&sql.QualifiedName{Column: "c"}},
 					From: &sql.TableScanNode{
 						RelationName: "t1",
 					},
@@ -103,24 +193,24 @@ func TestPlanner_Plan(t *testing.T) {
 		{
 			name: "plan with limit and offset and order by",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 				},
 				Limit: &sql.LimitClause{
 					Value: 10,
 				},
 				Offset:  &sql.OffsetClause{Value: 5},
-				OrderBy: &sql.OrderByClause{Fields: []*sql.Ident{{Name: "a"}}},
+				OrderBy: &sql.OrderByClause{Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}}},
 			},
 			want: &sql.LimitNode{
 				Value: 10,
 				From: &sql.OffsetNode{
 					Value: 5,
 					From: &sql.SortNode{
-						Keys: []string{"a"},
+						Keys: []sql.Expr{&sql.QualifiedName{Column: "a"}},
 						From: &sql.ProjectionNode{
-							Columns: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+							Columns: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 							From: &sql.TableScanNode{
 								RelationName: "t1",
 							},
@@ -132,16 +222,16 @@ func TestPlanner_Plan(t *testing.T) {
 		{
 			name: "plan with order by",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 				},
-				OrderBy: &sql.OrderByClause{Fields: []*sql.Ident{{Name: "a"}}},
+				OrderBy: &sql.OrderByClause{Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}}},
 			},
 			want: &sql.SortNode{
-				Keys: []string{"a"},
+				Keys: []sql.Expr{&sql.QualifiedName{Column: "a"}},
 				From: &sql.ProjectionNode{
-					Columns: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+					Columns: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 					From: &sql.TableScanNode{
 						RelationName: "t1",
 					},
@@ -151,13 +241,13 @@ func TestPlanner_Plan(t *testing.T) {
 		{
 			name: "plan with filter and unknown column",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 				},
 				Where: &sql.WhereClause{
 					Predicate: &sql.BinaryExpr{
-						LHS: &sql.Ident{Name: "first_name"},
+						LHS: &sql.QualifiedName{Column: "first_name"},
 						Op:  sql.EQ,
 						RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
 					},
@@ -165,17 +255,545 @@ func TestPlanner_Plan(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "plan with join",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}, &sql.QualifiedName{Column: "d"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+					Join: &sql.JoinSubClause{
+						TableName: &sql.QualifiedName{Table: "t2"},
+						Kind:      sql.LeftOuterJoin,
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
+							Op:  sql.LT,
+							RHS: &sql.QualifiedName{Table: "t2", Column: "a"},
+						},
+					},
+				},
+			},
+			want: &sql.ProjectionNode{
+				Columns: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}, &sql.QualifiedName{Column: "d"}},
+				From: &sql.NestedLoopNode{
+					Kind: sql.LeftOuterJoin,
+					Criterion: &sql.BinaryExpr{
+						LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
+						Op:  sql.LT,
+						RHS: &sql.QualifiedName{Table: "t2", Column: "a"},
+					},
+					Outer: &sql.TableScanNode{RelationName: "t1"},
+					Inner: &sql.TableScanNode{RelationName: "t2"},
+				},
+			},
+		},
+		{
+			name: "plan with equi join",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}, &sql.QualifiedName{Column: "d"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+					Join: &sql.JoinSubClause{
+						TableName: &sql.QualifiedName{Table: "t2"},
+						Kind:      sql.InnerJoin,
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "t2", Column: "a"},
+							Op:  sql.EQ,
+							RHS: &sql.QualifiedName{Table: "t1", Column: "a"},
+						},
+					},
+				},
+			},
+			want: &sql.ProjectionNode{
+				Columns: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}, &sql.QualifiedName{Column: "d"}},
+				From: &sql.HashJoinNode{
+					Kind: sql.InnerJoin,
+					Criterion: &sql.BinaryExpr{
+						LHS: &sql.QualifiedName{Table: "t2", Column: "a"},
+						Op:  sql.EQ,
+						RHS: &sql.QualifiedName{Table: "t1", Column: "a"},
+					},
+					OuterKey: &sql.QualifiedName{Table: "t1", Column: "a"},
+					InnerKey: &sql.QualifiedName{Table: "t2", Column: "a"},
+					Outer:    &sql.TableScanNode{RelationName: "t1"},
+					Inner:    &sql.TableScanNode{RelationName: "t2"},
+				},
+			},
+		},
+		{
+			name: "plan with equi join on sorted relation",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}, &sql.QualifiedName{Column: "e"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+					Join: &sql.JoinSubClause{
+						TableName: &sql.QualifiedName{Table: "t3"},
+						Kind:      sql.FullOuterJoin,
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
+							Op:  sql.EQ,
+							RHS: &sql.QualifiedName{Table: "t3", Column: "a"},
+						},
+					},
+				},
+			},
+			want: &sql.ProjectionNode{
+				Columns: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}, &sql.QualifiedName{Column: "e"}},
+				From: &sql.MergeJoinNode{
+					Kind: sql.FullOuterJoin,
+					Criterion: &sql.BinaryExpr{
+						LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
+						Op:  sql.EQ,
+						RHS: &sql.QualifiedName{Table: "t3", Column: "a"},
+					},
+					OuterKey: &sql.QualifiedName{Table: "t1", Column: "a"},
+					InnerKey: &sql.QualifiedName{Table: "t3", Column: "a"},
+					Outer: &sql.SortNode{
+						Keys: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}},
+						From: &sql.TableScanNode{RelationName: "t1"},
+					},
+					Inner: &sql.TableScanNode{RelationName: "t3"},
+				},
+			},
+		},
+		{
+			name: "plan with aliases",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.AliasExpr{Expr: &sql.QualifiedName{Table: "x", Column: "a"}, Alias: sql.Ident{Name: "k"}},
+					&sql.QualifiedName{Table: "y", Column: "e"},
+				},
+				From: sql.FromClause{
+					TableName: &sql.AliasExpr{Expr: &sql.QualifiedName{Table: "t1"}, Alias: sql.Ident{Name: "x"}},
+					Join: &sql.JoinSubClause{
+						TableName: &sql.AliasExpr{Expr: &sql.QualifiedName{Table: "t3"}, Alias: sql.Ident{Name: "y"}},
+						Kind:      sql.InnerJoin,
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "x", Column: "a"},
+							Op:  sql.EQ,
+							RHS: &sql.QualifiedName{Table: "y", Column: "a"},
+						},
+					},
+				},
+			},
+			want: &sql.ProjectionNode{
+				Columns: []sql.Expr{
+					&sql.AliasExpr{Expr: &sql.QualifiedName{Table: "x", Column: "a"}, Alias: sql.Ident{Name: "k"}},
+					&sql.QualifiedName{Table: "y", Column: "e"},
+				},
+				From: &sql.MergeJoinNode{
+					Kind: sql.InnerJoin,
+					Criterion: &sql.BinaryExpr{
+						LHS: &sql.QualifiedName{Table: "x", Column: "a"},
+						Op:  sql.EQ,
+						RHS: &sql.QualifiedName{Table: "y", Column: "a"},
+					},
+					OuterKey: &sql.QualifiedName{Table: "x", Column: "a"},
+					InnerKey: &sql.QualifiedName{Table: "y", Column: "a"},
+					Outer: &sql.SortNode{
+						Keys: []sql.Expr{&sql.QualifiedName{Table: "x", Column: "a"}},
+						From: &sql.TableScanNode{RelationName: "t1", Alias: "x"},
+					},
+					Inner: &sql.TableScanNode{RelationName: "t3", Alias: "y"},
+				},
+			},
+		},
+		{
+			name: "plan with relation hidden by its alias",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.AliasExpr{Expr: &sql.QualifiedName{Table: "t1"}, Alias: sql.Ident{Name: "x"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with ambiguous column",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "c"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+					Join: &sql.JoinSubClause{
+						TableName: &sql.AliasExpr{Expr: &sql.QualifiedName{Table: "t1"}, Alias: sql.Ident{Name: "other"}},
+						Kind:      sql.InnerJoin,
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
+							Op:  sql.EQ,
+							RHS: &sql.QualifiedName{Table: "other", Column: "a"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with self join",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "c"}, &sql.QualifiedName{Table: "other", Column: "c"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+					Join: &sql.JoinSubClause{
+						TableName: &sql.AliasExpr{Expr: &sql.QualifiedName{Table: "t1"}, Alias: sql.Ident{Name: "other"}},
+						Kind:      sql.InnerJoin,
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
+							Op:  sql.LT,
+							RHS: &sql.QualifiedName{Table: "other", Column: "a"},
+						},
+					},
+				},
+			},
+			want: &sql.ProjectionNode{
+				Columns: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "c"}, &sql.QualifiedName{Table: "other", Column: "c"}},
+				From: &sql.NestedLoopNode{
+					Kind: sql.InnerJoin,
+					Criterion: &sql.BinaryExpr{
+						LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
+						Op:  sql.LT,
+						RHS: &sql.QualifiedName{Table: "other", Column: "a"},
+					},
+					Outer: &sql.TableScanNode{RelationName: "t1"},
+					Inner: &sql.TableScanNode{RelationName: "t1", Alias: "other"},
+				},
+			},
+		},
+		{
+			name: "plan with is null on unknown column",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.UnaryExpr{Op: sql.NOT, X: &sql.IsNullExpr{X: &sql.QualifiedName{Column: "z"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with join on unknown column",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+					Join: &sql.JoinSubClause{
+						TableName: &sql.QualifiedName{Table: "t2"},
+						Kind:      sql.InnerJoin,
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
+							Op:  sql.EQ,
+							RHS: &sql.QualifiedName{Table: "t2", Column: "b"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with join on unknown relation",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Table: "t1", Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+					Join: &sql.JoinSubClause{
+						TableName: &sql.QualifiedName{Table: "t9"},
+						Kind:      sql.InnerJoin,
+						Criterion: &sql.BinaryExpr{
+							LHS: &sql.QualifiedName{Table: "t1", Column: "a"},
+							Op:  sql.EQ,
+							RHS: &sql.QualifiedName{Table: "t9", Column: "a"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with group by",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.QualifiedName{Column: "c"},
+					&sql.AggregateExpr{Func: sql.SumAggregate, Arg: &sql.QualifiedName{Column: "a"}},
+					&sql.AggregateExpr{Func: sql.CountAggregate},
+					&sql.AggregateExpr{Func: sql.SumAggregate, Arg: &sql.QualifiedName{Column: "a"}},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				GroupBy: &sql.GroupByClause{Fields: []*sql.QualifiedName{{Table: "t1", Column: "c"}}},
+			},
+			want: &sql.ProjectionNode{
+				Columns: []sql.Expr{
+					&sql.QualifiedName{Column: "c"},
+					&sql.AggregateExpr{Func: sql.SumAggregate, Arg: &sql.QualifiedName{Column: "a"}},
+					&sql.AggregateExpr{Func: sql.CountAggregate},
+					&sql.AggregateExpr{Func: sql.SumAggregate, Arg: &sql.QualifiedName{Column: "a"}},
+				},
+				From: &sql.HashAggregateNode{
+					Groups: []*sql.QualifiedName{{Table: "t1", Column: "c"}},
+					Aggregates: []*sql.AggregateExpr{
+						{Func: sql.SumAggregate, Arg: &sql.QualifiedName{Column: "a"}},
+						{Func: sql.CountAggregate},
+					},
+					From: &sql.TableScanNode{RelationName: "t1"},
+				},
+			},
+		},
+		{
+			name: "plan with group by on sorted relation",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{
+					&sql.QualifiedName{Column: "a"},
+					&sql.AggregateExpr{Func: sql.MinAggregate, Arg: &sql.QualifiedName{Column: "e"}},
+				},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t3"},
+				},
+				GroupBy: &sql.GroupByClause{Fields: []*sql.QualifiedName{{Column: "a"}}},
+			},
+			want: &sql.ProjectionNode{
+				Columns: []sql.Expr{
+					&sql.QualifiedName{Column: "a"},
+					&sql.AggregateExpr{Func: sql.MinAggregate, Arg: &sql.QualifiedName{Column: "e"}},
+				},
+				From: &sql.GroupAggregateNode{
+					Groups: []*sql.QualifiedName{{Column: "a"}},
+					Aggregates: []*sql.AggregateExpr{
+						{Func: sql.MinAggregate, Arg: &sql.QualifiedName{Column: "e"}},
+					},
+					From: &sql.TableScanNode{RelationName: "t3"},
+				},
+			},
+		},
+		{
+			name: "plan with aggregate and no group by",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.AggregateExpr{Func: sql.CountAggregate}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+			},
+			want: &sql.ProjectionNode{
+				Columns: []sql.Expr{&sql.AggregateExpr{Func: sql.CountAggregate}},
+				From: &sql.GroupAggregateNode{
+					Aggregates: []*sql.AggregateExpr{{Func: sql.CountAggregate}},
+					From:       &sql.TableScanNode{RelationName: "t1"},
+				},
+			},
+		},
+		{
+			name: "plan with column not in group by",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				GroupBy: &sql.GroupByClause{Fields: []*sql.QualifiedName{{Column: "a"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with having",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "c"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				GroupBy: &sql.GroupByClause{Fields: []*sql.QualifiedName{{Column: "c"}}},
+				Having: &sql.HavingClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.AggregateExpr{Func: sql.MaxAggregate, Arg: &sql.QualifiedName{Column: "a"}},
+						Op:  sql.GT,
+						RHS: &sql.BasicLit{Kind: sql.INT, Value: "2"},
+					},
+				},
+			},
+			want: &sql.ProjectionNode{
+				Columns: []sql.Expr{&sql.QualifiedName{Column: "c"}},
+				From: &sql.FilterNode{
+					Filter: &sql.BinaryExpr{
+						LHS: &sql.AggregateExpr{Func: sql.MaxAggregate, Arg: &sql.QualifiedName{Column: "a"}},
+						Op:  sql.GT,
+						RHS: &sql.BasicLit{Kind: sql.INT, Value: "2"},
+					},
+					From: &sql.HashAggregateNode{
+						Groups:     []*sql.QualifiedName{{Column: "c"}},
+						Aggregates: []*sql.AggregateExpr{{Func: sql.MaxAggregate, Arg: &sql.QualifiedName{Column: "a"}}},
+						From:       &sql.TableScanNode{RelationName: "t1"},
+					},
+				},
+			},
+		},
+		{
+			name: "plan with having on column not in group by",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "c"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				GroupBy: &sql.GroupByClause{Fields: []*sql.QualifiedName{{Column: "c"}}},
+				Having: &sql.HavingClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.QualifiedName{Column: "a"},
+						Op:  sql.GT,
+						RHS: &sql.BasicLit{Kind: sql.INT, Value: "2"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with aggregate in where",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "c"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.AggregateExpr{Func: sql.CountAggregate},
+						Op:  sql.GT,
+						RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
+					},
+				},
+				GroupBy: &sql.GroupByClause{Fields: []*sql.QualifiedName{{Column: "c"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with aggregate on unknown column",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.AggregateExpr{Func: sql.SumAggregate, Arg: &sql.QualifiedName{Column: "z"}}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "plan with offset and no limit",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 				},
 				Offset: &sql.OffsetClause{Value: 10},
 			},
 			wantErr: true,
 		},
+		{
+			name: "plan with unknown function",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.CallExpr{Name: "nope", Args: []sql.Expr{&sql.QualifiedName{Column: "a"}}}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with function argument of the wrong type",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.CallExpr{Name: "abs", Args: []sql.Expr{&sql.QualifiedName{Column: "c"}}},
+						Op:  sql.GT,
+						RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with comparison of incompatible types",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.InExpr{X: &sql.QualifiedName{Column: "c"}, List: []sql.Expr{&sql.BasicLit{Kind: sql.STRING, Value: "x"}, &sql.QualifiedName{Column: "a"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with invalid cast",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.CastExpr{X: &sql.QualifiedName{Column: "b"}, Type: sql.DATETIME}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with AND on non boolean operands",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{
+						LHS: &sql.QualifiedName{Column: "a"},
+						Op:  sql.AND,
+						RHS: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "b"}, Op: sql.GT, RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with non boolean where",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "a"}, Op: sql.PLUS, RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with comparison of text and integer",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+				Where: &sql.WhereClause{
+					Predicate: &sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "c"}, Op: sql.EQ, RHS: &sql.QualifiedName{Column: "a"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with sum of text",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.AggregateExpr{Func: sql.SumAggregate, Arg: &sql.QualifiedName{Column: "c"}}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with arithmetic on text",
+			stmt: &sql.SelectStmt{
+				Fields: []sql.Expr{&sql.BinaryExpr{LHS: &sql.QualifiedName{Column: "c"}, Op: sql.MINUS, RHS: &sql.BasicLit{Kind: sql.INT, Value: "2"}}},
+				From: sql.FromClause{
+					TableName: &sql.QualifiedName{Table: "t1"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "plan with limit and no projection",
 			stmt: &sql.SelectStmt{
@@ -199,23 +817,213 @@ func TestPlanner_Plan(t *testing.T) {
 	}
 }
 
+func TestPlanner_Columns(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    []sql.ResultColumn
+		wantErr bool
+	}{
+		{
+			query: `SELECT * FROM t1`,
+			want: []sql.ResultColumn{
+				{Table: "t1", Name: "a", Type: sql.INTEGER},
+				{Table: "t1", Name: "b", Type: sql.REAL},
+				{Table: "t1", Name: "c", Type: sql.TEXT},
+			},
+		},
+		{
+			query: `SELECT a AS x, b * 2, c || 'x', a > 1 FROM t1 WHERE b IS NOT NULL ORDER BY x`,
+			want: []sql.ResultColumn{
+				{Name: "x", Type: sql.INTEGER},
+				{Name: "b * 2", Type: sql.REAL},
+				{Name: "c || 'x'", Type: sql.TEXT},
+				{Name: "a > 1", Type: sql.BOOLEAN},
+			},
+		},
+		{
+			query: `SELECT c, COUNT(*), AVG(a), SUM(a) + 1 FROM t1 GROUP BY c HAVING MAX(b) > 1`,
+			want: []sql.ResultColumn{
+				{Table: "t1", Name: "c", Type: sql.TEXT},
+				{Name: "COUNT(*)", Type: sql.INTEGER},
+				{Name: "AVG(a)", Type: sql.REAL},
+				{Name: "SUM(a) + 1", Type: sql.INTEGER},
+			},
+		},
+		{
+			query: `SELECT t1.a, d FROM t1 JOIN t2 ON t1.a = t2.a LIMIT 1`,
+			want: []sql.ResultColumn{
+				{Table: "t1", Name: "a", Type: sql.INTEGER},
+				{Table: "t2", Name: "d", Type: sql.TEXT},
+			},
+		},
+		{
+			query: `SELECT t2.*, t1.c FROM t1 JOIN t2 ON t1.a = t2.a`,
+			want: []sql.ResultColumn{
+				{Table: "t2", Name: "a", Type: sql.INTEGER},
+				{Table: "t2", Name: "d", Type: sql.TEXT},
+				{Table: "t1", Name: "c", Type: sql.TEXT},
+			},
+		},
+		{query: `SELECT a FROM t1 JOIN t2 ON t1.a = t2.a`, wantErr: true},
+		{query: `SELECT COUNT(*) FROM t1 JOIN t2 ON t1.a = t2.a GROUP BY a`, wantErr: true},
+		{
+			query: `SELECT public.t1.a, t1."c" || 'x' FROM public.t1`,
+			want: []sql.ResultColumn{
+				{Table: "t1", Name: "a", Type: sql.INTEGER},
+				{Name: "t1.c || 'x'", Type: sql.TEXT},
+			},
+		},
+		{query: `SELECT a FROM other.t1`, wantErr: true},
+		{query: `SELECT other.t1.a FROM public.t1`, wantErr: true},
+		{query: `SELECT x.* FROM t1`, wantErr: true},
+		{query: `SELECT s.t1.a FROM t1`, wantErr: true},
+		{query: `SELECT a FROM t1 ORDER BY z`, wantErr: true},
+		{query: `SELECT DISTINCT a FROM t1 ORDER BY b`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			stmt, err := sql.NewParser(strings.NewReader(tt.query)).Parse()
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			p := sql.NewPlanner(&mockCatalog{})
+			plan, err := p.Plan(stmt)
+			if err == nil {
+				var got []sql.ResultColumn
+				got, err = p.Columns(plan)
+				if err == nil && !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Columns() = %v, want %v", got, tt.want)
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Columns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlanner_Check(t *testing.T) {
+	stmt, err := sql.NewParser(strings.NewReader(`SELECT a + b, -a, UPPER(c) FROM t1 WHERE c LIKE 'x%' AND a IN (1, 2)`)).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	info, err := sql.NewPlanner(&mockCatalog{}).Check(stmt)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	sum := stmt.Fields[0].(*sql.BinaryExpr)
+	and := stmt.Where.Predicate.(*sql.BinaryExpr)
+	tests := []struct {
+		expr sql.Expr
+		want sql.DataType
+	}{
+		{expr: sum, want: sql.REAL},
+		{expr: sum.LHS, want: sql.INTEGER},
+		{expr: sum.RHS, want: sql.REAL},
+		{expr: stmt.Fields[1], want: sql.INTEGER},
+		{expr: stmt.Fields[2], want: sql.TEXT},
+		{expr: and, want: sql.BOOLEAN},
+		{expr: and.LHS.(*sql.LikeExpr).Pattern, want: sql.TEXT},
+		{expr: and.RHS.(*sql.InExpr).List[1], want: sql.INTEGER},
+	}
+	for _, tt := range tests {
+		if got := info.TypeOf(tt.expr); got != tt.want {
+			t.Errorf("TypeOf(%s) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	stmt, err = sql.NewParser(strings.NewReader(`SELECT a FROM t1 WHERE NOT c`)).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := sql.NewPlanner(&mockCatalog{}).Check(stmt); err == nil {
+		t.Errorf("Check() expected error for NOT on TEXT")
+	}
+}
+
 var testRelations = map[string]sql.Relation{
 	"t1": {
 		Name:     "t1",
 		Location: nil,
-		Schema: map[string]sql.Column{
-			"a": {
+		Columns: []sql.Column{
+			{
 				Name:     "a",
 				Type:     sql.INTEGER,
 				Location: nil,
 			},
-			"b": {
+			{
 				Name:     "b",
 				Type:     sql.REAL,
 				Location: nil,
 			},
-			"c": {
-				Name:     "b",
+			{
+				Name:     "c",
+				Type:     sql.TEXT,
+				Location: nil,
+			},
+		},
+	},
+	"t2": {
+		Name:     "t2",
+		Location: nil,
+		Columns: []sql.Column{
+			{
+				Name:     "a",
+				Type:     sql.INTEGER,
+				Location: nil,
+			},
+			{
+				Name:     "d",
+				Type:     sql.TEXT,
+				Location: nil,
+			},
+		},
+	},
+	"t3": {
+		Name:     "t3",
+		Location: nil,
+		Columns: []sql.Column{
+			{
+				Name:     "a",
+				Type:     sql.INTEGER,
+				Location: nil,
+			},
+			{
+				Name:     "e",
+				Type:     sql.TEXT,
+				Location: nil,
+			},
+		},
+		SortedBy: []string{"a"},
+	},
+	"events": {
+		Name:     "events",
+		Location: nil,
+		Columns: []sql.Column{
+			{
+				Name:     "id",
+				Type:     sql.INTEGER,
+				Location: nil,
+			},
+			{
+				Name:     "at",
+				Type:     sql.DATETIME,
+				Location: nil,
+			},
+		},
+	},
+	"holidays": {
+		Name:     "holidays",
+		Location: nil,
+		Columns: []sql.Column{
+			{
+				Name:     "day",
+				Type:     sql.TEXT,
+				Location: nil,
+			},
+			{
+				Name:     "name",
 				Type:     sql.TEXT,
 				Location: nil,
 			},
@@ -226,17 +1034,12 @@ var testRelations = map[string]sql.Relation{
 type mockCatalog struct {
 }
 
-func (m *mockCatalog) HasColumn(columnName string) bool {
-	for _, v := range testRelations {
-		if v.HasColumn(columnName) {
-			return true
-		}
+// GetRelation finds the test relations, which are in the default schema public.
+func (m *mockCatalog) GetRelation(name *sql.QualifiedName) (sql.Relation, error) {
+	if name.Catalog != "" || (name.Schema != "" && name.Schema != "public") {
+		return sql.Relation{}, errors.New("no relation")
 	}
-	return false
-}
-
-func (m *mockCatalog) GetRelation(s string) (sql.Relation, error) {
-	r, ok := testRelations[s]
+	r, ok := testRelations[name.Table]
 	if !ok {
 		return sql.Relation{}, errors.New("no relation")
 	}
@@ -251,36 +1054,36 @@ func BenchmarkPlanner_Plan(b *testing.B) {
 		{
 			name: "simple plan",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 				},
 			},
 		},
 		{
 			name: "plan with limit and offset and order by",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 				},
 				Limit: &sql.LimitClause{
 					Value: 10,
 				},
 				Offset:  &sql.OffsetClause{Value: 5},
-				OrderBy: &sql.OrderByClause{Fields: []*sql.Ident{{Name: "a"}}},
+				OrderBy: &sql.OrderByClause{Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}}},
 			},
 		},
 		{
 			name: "plan with filter and unknown column",
 			stmt: &sql.SelectStmt{
-				Fields: []sql.Ident{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Fields: []sql.Expr{&sql.QualifiedName{Column: "a"}, &sql.QualifiedName{Column: "b"}, &sql.QualifiedName{Column: "c"}},
 				From: sql.FromClause{
-					TableName: &sql.Ident{Name: "t1"},
+					TableName: &sql.QualifiedName{Table: "t1"},
 				},
 				Where: &sql.WhereClause{
 					Predicate: &sql.BinaryExpr{
-						LHS: &sql.Ident{Name: "first_name"},
+						LHS: &sql.QualifiedName{Column: "first_name"},
 						Op:  sql.EQ,
 						RHS: &sql.BasicLit{Kind: sql.INT, Value: "1"},
 					},
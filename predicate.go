@@ -0,0 +1,156 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// compileInExpr evaluates "x IN (a, b)" as "x = a OR x = b", which gives it the expected behaviour
+// with NULLs: the result is UNKNOWN when x is NULL, or when no value matches and one of them is NULL.
+func compileInExpr(e *InExpr, cols []ResultColumn) (evalFunc, error) {
+	if len(e.List) == 0 {
+		return nil, errors.New("invalid expression: empty IN list")
+	}
+
+	var expr Expr
+	for _, v := range e.List {
+		eq := &BinaryExpr{LHS: e.X, Op: EQ, RHS: v}
+		if expr == nil {
+			expr = eq
+		} else {
+			expr = &BinaryExpr{LHS: expr, Op: OR, RHS: eq}
+		}
+	}
+	if e.Not {
+		expr = &UnaryExpr{Op: NOT, X: expr}
+	}
+	return compileExpr(expr, cols)
+}
+
+// compileBetweenExpr evaluates "x BETWEEN low AND high" as "x >= low AND x <= high".
+func compileBetweenExpr(e *BetweenExpr, cols []ResultColumn) (evalFunc, error) {
+	var expr Expr = &BinaryExpr{
+		LHS: &BinaryExpr{LHS: e.X, Op: GTE, RHS: e.Low},
+		Op:  AND,
+		RHS: &BinaryExpr{LHS: e.X, Op: LTE, RHS: e.High},
+	}
+	if e.Not {
+		expr = &UnaryExpr{Op: NOT, X: expr}
+	}
+	return compileExpr(expr, cols)
+}
+
+// compileLikeExpr matches a TEXT value against a pattern, the result is UNKNOWN when any operand is NULL.
+// Without ESCAPE clause, no character escapes the wildcards.
+func compileLikeExpr(e *LikeExpr, cols []ResultColumn) (evalFunc, error) {
+	x, err := compileExpr(e.X, cols)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := compileExpr(e.Pattern, cols)
+	if err != nil {
+		return nil, err
+	}
+	var escape evalFunc
+	if e.Escape != nil {
+		if escape, err = compileExpr(e.Escape, cols); err != nil {
+			return nil, err
+		}
+	}
+
+	not := e.Not
+	return func(row Row) (Value, error) {
+		var args [3]string
+		for i, fn := range []evalFunc{x, pattern, escape} {
+			if fn == nil {
+				continue
+			}
+			v, err := fn(row)
+			if err != nil || v == nil {
+				return nil, err
+			}
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("LIKE expects TEXT operands, got %v", v)
+			}
+			args[i] = s
+		}
+
+		esc := rune(-1)
+		switch utf8.RuneCountInString(args[2]) {
+		case 0:
+		case 1:
+			esc, _ = utf8.DecodeRuneInString(args[2])
+		default:
+			return nil, fmt.Errorf("invalid escape string \"%s\", it must be a single character", args[2])
+		}
+
+		ok, err := matchLike(args[0], args[1], esc)
+		if err != nil {
+			return nil, err
+		}
+		return ok != not, nil
+	}, nil
+}
+
+// likeElem is an element of a LIKE pattern: a character to match, or a wildcard.
+type likeElem struct {
+	r        rune
+	wildcard rune
+}
+
+// parseLike splits a LIKE pattern in elements, escape is -1 when the pattern has no escape character.
+func parseLike(pattern string, escape rune) ([]likeElem, error) {
+	var elems []likeElem
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			elems = append(elems, likeElem{r: r})
+			escaped = false
+		case r == escape:
+			escaped = true
+		case r == '%' || r == '_':
+			elems = append(elems, likeElem{wildcard: r})
+		default:
+			elems = append(elems, likeElem{r: r})
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("LIKE pattern \"%s\" must not end with the escape character", pattern)
+	}
+	return elems, nil
+}
+
+// matchLike reports whether s matches the LIKE pattern.
+// On a mismatch, the matching restarts after the last % with one more character consumed by it.
+func matchLike(s, pattern string, escape rune) (bool, error) {
+	elems, err := parseLike(pattern, escape)
+	if err != nil {
+		return false, err
+	}
+
+	str := []rune(s)
+	si, pi := 0, 0
+	star, mark := -1, 0
+	for si < len(str) {
+		switch {
+		case pi < len(elems) && elems[pi].wildcard == '%':
+			star, mark = pi, si
+			pi++
+		case pi < len(elems) && (elems[pi].wildcard == '_' || (elems[pi].wildcard == 0 && elems[pi].r == str[si])):
+			si++
+			pi++
+		case star >= 0:
+			mark++
+			si, pi = mark, star+1
+		default:
+			return false, nil
+		}
+	}
+	for pi < len(elems) && elems[pi].wildcard == '%' {
+		pi++
+	}
+	return pi == len(elems), nil
+}
@@ -0,0 +1,43 @@
+package sql
+
+import "testing"
+
+func Test_matchLike(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		pattern string
+		escape  rune
+		want    bool
+		wantErr bool
+	}{
+		{name: "exact", s: "abc", pattern: "abc", escape: -1, want: true},
+		{name: "case sensitive", s: "abc", pattern: "ABC", escape: -1, want: false},
+		{name: "prefix", s: "Alice", pattern: "A%", escape: -1, want: true},
+		{name: "suffix", s: "Alice", pattern: "%ce", escape: -1, want: true},
+		{name: "infix", s: "Alice", pattern: "%li%", escape: -1, want: true},
+		{name: "backtracking", s: "abcbcd", pattern: "a%bcd", escape: -1, want: true},
+		{name: "single character", s: "abc", pattern: "a_c", escape: -1, want: true},
+		{name: "single character is required", s: "ac", pattern: "a_c", escape: -1, want: false},
+		{name: "empty string", s: "", pattern: "%", escape: -1, want: true},
+		{name: "empty pattern", s: "a", pattern: "", escape: -1, want: false},
+		{name: "multibyte", s: "été", pattern: "_t_", escape: -1, want: true},
+		{name: "escaped percent", s: "10%", pattern: "10!%", escape: '!', want: true},
+		{name: "escaped percent is literal", s: "100", pattern: "10!%", escape: '!', want: false},
+		{name: "escaped escape", s: "a!b", pattern: "a!!b", escape: '!', want: true},
+		{name: "no escape character", s: `a\b`, pattern: `a\b`, escape: -1, want: true},
+		{name: "trailing escape", s: "a", pattern: "a!", escape: '!', wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchLike(tt.s, tt.pattern, tt.escape)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("matchLike() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("matchLike() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,80 +2,209 @@ package sql
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 var eof = rune(0)
 var bufSizeHint = 32
 
+// Pos is a position in the source of a statement.
+type Pos struct {
+	// Offset is the offset in bytes, starting at 0.
+	Offset int
+	// Line and Column start at 1, the columns count characters.
+	Line   int
+	Column int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
 type Lexeme struct {
 	Token Token
 	Lit   string
+	// Pos is the position of the first character of the lexeme.
+	Pos Pos
 }
 
 type Scanner struct {
+	// Comments makes the scanner return the comments as COMMENT lexemes, they are skipped otherwise.
+	Comments bool
+	// Backticks makes the scanner accept identifiers quoted with backticks, as in MySQL, besides double quotes.
+	Backticks bool
+
 	r *bufio.Reader
+	// pos is the position of the next character, prev the one of the last character read.
+	pos  Pos
+	prev Pos
+	// src holds the source read so far.
+	src []byte
+	// err describes the last lexeme when it is a malformed ILLEGAL one.
+	err error
 }
 
 func NewScanner(r *strings.Reader) *Scanner {
-	s := Scanner{r: bufio.NewReader(r)}
+	s := Scanner{r: bufio.NewReader(r), pos: Pos{Line: 1, Column: 1}}
 	return &s
 }
 
+// Scan returns the next lexeme. Comments start with -- and run to the end of the line,
+// or are enclosed in /* and */ and can be nested.
+// Quoted identifiers are enclosed in double quotes, a quote being written twice inside the identifier.
+// They are never keywords and can hold any character. The parts of a qualified name, as in "my schema".t,
+// are separate IDENT lexemes with DOT lexemes between them.
+// Strings are enclosed in single quotes, a quote being written twice inside a string. Strings prefixed
+// with E, as in E'a\tb', accept the escape sequences of C, see scanEscape. The literal of a STRING lexeme is its value.
 func (s *Scanner) Scan() Lexeme {
-	var ch rune
-	ch = s.read()
+	s.err = nil
+	pos := s.pos
+	ch := s.read()
 	var lex Lexeme
 	switch {
 	case isWhitespace(ch):
 		s.unread()
 		s.skipWhitespaces()
-		lex = s.Scan()
+		return s.Scan()
 	case unicode.IsLetter(ch):
 		s.unread()
 		lit := s.scanLiterals()
+		if strings.EqualFold(lit, "E") && s.peek() == '\'' {
+			s.read()
+			lex = s.scanString(pos, true)
+			break
+		}
 		tok := tokenizeLiteral(lit)
-		lex = Lexeme{tok, lit}
+		lex = Lexeme{Token: tok, Lit: lit}
 	case isComparisonOperator(ch):
 		s.unread()
 		lit := s.scanOperators()
 		tok := tokenizeOperators(lit)
-		lex = Lexeme{tok, lit}
-	case unicode.IsDigit(ch) || isSign(ch):
+		lex = Lexeme{Token: tok, Lit: lit}
+	case unicode.IsDigit(ch):
 		s.unread()
 		lit := s.scanNumerics()
 		tok := tokenizeNumerics(lit)
-		lex = Lexeme{tok, lit}
-	case isQuotationMark(ch):
-		s.unread()
-		lit := s.scanStringLiterals()
-		lex = Lexeme{STRING, lit}
+		lex = Lexeme{Token: tok, Lit: lit}
+	case ch == '\'':
+		lex = s.scanString(pos, false)
+	case s.isIdentQuote(ch):
+		lex = s.scanQuotedIdent(pos, ch)
+	case ch == '-' && s.peek() == '-':
+		lex = Lexeme{Token: COMMENT, Lit: s.scanLineComment()}
+	case ch == '/' && s.peek() == '*':
+		lit, ok := s.scanBlockComment()
+		if !ok {
+			s.err = errors.New("unterminated comment")
+			return Lexeme{Token: ILLEGAL, Lit: lit, Pos: pos}
+		}
+		lex = Lexeme{Token: COMMENT, Lit: lit}
 	case ch == eof:
-		lex = Lexeme{EOF, ""}
+		lex = Lexeme{Token: EOF, Lit: ""}
 	case ch == '*':
-		lex = Lexeme{ASTERISK, "*"}
+		lex = Lexeme{Token: ASTERISK, Lit: "*"}
+	case ch == '+':
+		lex = Lexeme{Token: PLUS, Lit: "+"}
+	case ch == '-':
+		lex = Lexeme{Token: MINUS, Lit: "-"}
+	case ch == '/':
+		lex = Lexeme{Token: SLASH, Lit: "/"}
+	case ch == '%':
+		lex = Lexeme{Token: PERCENT, Lit: "%"}
+	case ch == '|':
+		if s.peek() != '|' {
+			lex = Lexeme{Token: ILLEGAL, Lit: string(ch)}
+			break
+		}
+		s.read()
+		lex = Lexeme{Token: CONCAT, Lit: "||"}
+	case ch == ':':
+		if s.peek() != ':' {
+			lex = Lexeme{Token: ILLEGAL, Lit: string(ch)}
+			break
+		}
+		s.read()
+		lex = Lexeme{Token: TYPECAST, Lit: "::"}
 	case ch == ',':
-		lex = Lexeme{COMMA, ","}
+		lex = Lexeme{Token: COMMA, Lit: ","}
+	case ch == '.':
+		lex = Lexeme{Token: DOT, Lit: "."}
 	case ch == ';':
-		lex = Lexeme{SEMICOLON, ";"}
+		lex = Lexeme{Token: SEMICOLON, Lit: ";"}
+	case ch == '(':
+		lex = Lexeme{Token: LPAREN, Lit: "("}
+	case ch == ')':
+		lex = Lexeme{Token: RPAREN, Lit: ")"}
 	default:
-		lex = Lexeme{ILLEGAL, string(ch)}
+		lex = Lexeme{Token: ILLEGAL, Lit: string(ch)}
+	}
+	if lex.Token == COMMENT && !s.Comments {
+		return s.Scan()
 	}
+	lex.Pos = pos
 	return lex
 }
 
+// Err describes the last lexeme returned by Scan when it is ILLEGAL because it is malformed,
+// such as an unterminated comment. It is nil for the other lexemes.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
 func (s *Scanner) read() rune {
-	ch, _, err := s.r.ReadRune()
+	s.prev = s.pos
+	ch, size, err := s.r.ReadRune()
 	if err != nil {
 		return eof
 	}
+	if s.pos.Offset == len(s.src) {
+		s.src = append(s.src, string(ch)...)
+	}
+	s.pos.Offset += size
+	if ch == '\n' {
+		s.pos.Line++
+		s.pos.Column = 1
+	} else {
+		s.pos.Column++
+	}
 	return ch
 }
 
 func (s *Scanner) unread() {
-	_ = s.r.UnreadRune()
+	if err := s.r.UnreadRune(); err == nil {
+		s.pos = s.prev
+	}
+}
+
+// snippet returns the line of the source holding the position, followed by a line with a caret under it.
+func (s *Scanner) snippet(pos Pos) string {
+	// Read the rest of the line.
+	for {
+		if ch := s.read(); ch == eof || ch == '\n' {
+			break
+		}
+	}
+
+	start := strings.LastIndexByte(string(s.src[:pos.Offset]), '\n') + 1
+	end := len(s.src)
+	if i := strings.IndexByte(string(s.src[pos.Offset:]), '\n'); i != -1 {
+		end = pos.Offset + i
+	}
+	line := string(s.src[start:end])
+
+	// Keep the tabs, so that the caret is aligned.
+	caret := []rune(string(s.src[start:pos.Offset]))
+	for i, ch := range caret {
+		if ch != '\t' {
+			caret[i] = ' '
+		}
+	}
+	return line + "\n" + string(caret) + "^"
 }
 
 func (s *Scanner) peek() rune {
@@ -90,10 +219,9 @@ func (s *Scanner) scanLiterals() string {
 	for {
 		ch := s.read()
 		if !isAlphanumeric(ch) {
-			// Special cases:
-			// - underscore in identifiers names are acceptable
-			// - dot for table qualified name, ex. db.schema.table
-			if (ch == '_') || (ch == '.' && s.peek() != '.') {
+			// Underscores in identifiers names are acceptable. The parts of qualified names,
+			// ex. db.schema.table, are separate lexemes.
+			if ch == '_' {
 				sb.WriteRune(ch)
 				continue
 			}
@@ -105,6 +233,46 @@ func (s *Scanner) scanLiterals() string {
 	}
 }
 
+// scanLineComment scans a comment up to the end of the line, the first dash has already been read.
+func (s *Scanner) scanLineComment() string {
+	var sb strings.Builder
+	sb.Grow(bufSizeHint)
+	sb.WriteRune('-')
+	for {
+		ch := s.read()
+		if ch == eof || ch == '\n' {
+			s.unread()
+			return sb.String()
+		}
+		sb.WriteRune(ch)
+	}
+}
+
+// scanBlockComment scans a comment up to the end delimiter matching its start, the first slash has already been read.
+// It reports whether the comment is terminated.
+func (s *Scanner) scanBlockComment() (string, bool) {
+	var sb strings.Builder
+	sb.Grow(bufSizeHint)
+	sb.WriteRune('/')
+	sb.WriteRune(s.read())
+	for depth := 1; depth > 0; {
+		ch := s.read()
+		if ch == eof {
+			return sb.String(), false
+		}
+		sb.WriteRune(ch)
+		switch {
+		case ch == '*' && s.peek() == '/':
+			sb.WriteRune(s.read())
+			depth--
+		case ch == '/' && s.peek() == '*':
+			sb.WriteRune(s.read())
+			depth++
+		}
+	}
+	return sb.String(), true
+}
+
 func (s *Scanner) scanWhitespaces() Lexeme {
 	var sb strings.Builder
 	sb.Grow(bufSizeHint)
@@ -112,7 +280,7 @@ func (s *Scanner) scanWhitespaces() Lexeme {
 		ch := s.read()
 		if !isWhitespace(ch) {
 			s.unread()
-			return Lexeme{WS, sb.String()}
+			return Lexeme{Token: WS, Lit: sb.String()}
 		}
 
 		sb.WriteRune(ch)
@@ -145,19 +313,137 @@ func (s *Scanner) scanNumerics() string {
 	}
 }
 
-func (s *Scanner) scanStringLiterals() string {
+// scanString scans a string starting at pos, up to its closing quote. The opening quote has already been read.
+// Malformed strings are returned as ILLEGAL lexemes holding their source.
+func (s *Scanner) scanString(pos Pos, escapes bool) Lexeme {
 	var sb strings.Builder
 	sb.Grow(bufSizeHint)
+	for {
+		ch := s.read()
+		switch {
+		case ch == eof:
+			s.err = errors.New("unterminated string")
+		case ch == '\'' && s.peek() == '\'':
+			sb.WriteRune(s.read())
+		case ch == '\'':
+			if !utf8.ValidString(sb.String()) {
+				s.err = errors.New("invalid byte sequence in string")
+				break
+			}
+			return Lexeme{Token: STRING, Lit: sb.String()}
+		case ch == '\\' && escapes:
+			var esc string
+			if esc, s.err = s.scanEscape(); s.err == nil {
+				sb.WriteString(esc)
+			}
+		default:
+			sb.WriteRune(ch)
+		}
+		if s.err != nil {
+			return Lexeme{Token: ILLEGAL, Lit: string(s.src[pos.Offset:s.pos.Offset])}
+		}
+	}
+}
+
+// scanQuotedIdent scans an identifier starting at pos, the opening quote has already been read.
+// Malformed identifiers are returned as ILLEGAL lexemes holding their source.
+func (s *Scanner) scanQuotedIdent(pos Pos, quote rune) Lexeme {
+	var sb strings.Builder
+	sb.Grow(bufSizeHint)
+	for s.err == nil {
+		ch := s.read()
+		if ch == eof {
+			s.err = errors.New("unterminated quoted identifier")
+		} else if ch != quote {
+			sb.WriteRune(ch)
+		} else if s.peek() == quote {
+			sb.WriteRune(s.read())
+		} else {
+			break
+		}
+	}
+	if s.err == nil && sb.Len() == 0 {
+		s.err = errors.New("zero-length quoted identifier")
+	}
+	if s.err != nil {
+		return Lexeme{Token: ILLEGAL, Lit: string(s.src[pos.Offset:s.pos.Offset])}
+	}
+	return Lexeme{Token: IDENT, Lit: sb.String()}
+}
+
+// isIdentQuote checks if a character quotes identifiers.
+func (s *Scanner) isIdentQuote(ch rune) bool {
+	return ch == '"' || (ch == '`' && s.Backticks)
+}
+
+// escapes are the characters written with a backslash followed by a letter.
+var escapes = map[rune]rune{'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'}
+
+// scanEscape scans an escape sequence, the backslash has already been read, and returns what it stands for.
+// Besides \b, \f, \n, \r and \t, \xH and \xHH are bytes in hexadecimal, \O, \OO and \OOO bytes in octal,
+// \uXXXX and \UXXXXXXXX unicode code points in hexadecimal, and any other character stands for itself.
+// The bytes must make up valid UTF-8 characters, which is checked once the string is scanned.
+func (s *Scanner) scanEscape() (string, error) {
 	ch := s.read()
-	sb.WriteRune(ch)
+	if r, ok := escapes[ch]; ok {
+		return string(r), nil
+	}
+	var digits int
+	switch {
+	case ch == eof:
+		return "", errors.New("unterminated string")
+	case ch == 'x':
+		hex := s.scanDigits(2, 16)
+		if hex == "" {
+			return string(ch), nil
+		}
+		b, _ := strconv.ParseUint(hex, 16, 8)
+		return string([]byte{byte(b)}), nil
+	case ch >= '0' && ch <= '7':
+		s.unread()
+		oct := s.scanDigits(3, 8)
+		b, err := strconv.ParseUint(oct, 8, 8)
+		if err != nil {
+			return "", fmt.Errorf("invalid octal escape \\%s", oct)
+		}
+		return string([]byte{byte(b)}), nil
+	case ch == 'u':
+		digits = 4
+	case ch == 'U':
+		digits = 8
+	default:
+		return string(ch), nil
+	}
 
-	for {
+	var sb strings.Builder
+	for i := 0; i < digits; i++ {
+		if ch := s.read(); ch != eof {
+			sb.WriteRune(ch)
+		}
+	}
+	r, err := strconv.ParseUint(sb.String(), 16, 32)
+	if err != nil || !utf8.ValidRune(rune(r)) {
+		return "", fmt.Errorf("invalid unicode escape \\%c%s", ch, sb.String())
+	}
+	return string(rune(r)), nil
+}
+
+// scanDigits scans up to n digits in base 8 or 16.
+func (s *Scanner) scanDigits(n int, base int) string {
+	var sb strings.Builder
+	for sb.Len() < n {
 		ch := s.read()
-		sb.WriteRune(ch)
-		if isQuotationMark(ch) {
-			return sb.String()
+		isDigit := ch >= '0' && ch <= '7'
+		if base == 16 {
+			isDigit = unicode.Is(unicode.ASCII_Hex_Digit, ch)
+		}
+		if !isDigit {
+			s.unread()
+			break
 		}
+		sb.WriteRune(ch)
 	}
+	return sb.String()
 }
 
 func (s *Scanner) scanOperators() string {
@@ -190,18 +476,10 @@ func isWhitespace(ch rune) bool {
 	return unicode.Is(unicode.White_Space, ch)
 }
 
-func isQuotationMark(ch rune) bool {
-	return unicode.Is(unicode.Quotation_Mark, ch)
-}
-
 func isAlphanumeric(ch rune) bool {
 	return unicode.IsLetter(ch) || unicode.IsDigit(ch)
 }
 
-func isSign(ch rune) bool {
-	return ch == '-' || ch == '+'
-}
-
 func isComparisonOperator(ch rune) bool {
 	return ch == '=' || ch == '>' || ch == '<'
 
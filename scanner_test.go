@@ -26,16 +26,45 @@ func TestScanner_Scan(t *testing.T) {
 
 		// Misc characters
 		{s: `*`, item: sql.Lexeme{Token: sql.ASTERISK, Lit: "*"}},
+		{s: `::`, item: sql.Lexeme{Token: sql.TYPECAST, Lit: "::"}},
+		{s: `:`, item: sql.Lexeme{Token: sql.ILLEGAL, Lit: ":"}},
 
 		// Identifiers
 		{s: `foo`, item: sql.Lexeme{Token: sql.IDENT, Lit: `foo`}},
-		{s: `foo.bar.baz`, item: sql.Lexeme{Token: sql.IDENT, Lit: `foo.bar.baz`}},
+		{s: `foo.bar.baz`, item: sql.Lexeme{Token: sql.IDENT, Lit: `foo`}},
+		{s: `.bar`, item: sql.Lexeme{Token: sql.DOT, Lit: `.`}},
 		{s: `Zx12_3U_-`, item: sql.Lexeme{Token: sql.IDENT, Lit: `Zx12_3U_`}},
 
 		// String Literals
-		{s: `"yolo"`, item: sql.Lexeme{Token: sql.STRING, Lit: `"yolo"`}},
-		{s: `'yolo'`, item: sql.Lexeme{Token: sql.STRING, Lit: `'yolo'`}},
-		{s: `'this is a test'`, item: sql.Lexeme{Token: sql.STRING, Lit: `'this is a test'`}},
+		{s: `'yolo'`, item: sql.Lexeme{Token: sql.STRING, Lit: `yolo`}},
+		{s: `'this is a test'`, item: sql.Lexeme{Token: sql.STRING, Lit: `this is a test`}},
+		{s: `''`, item: sql.Lexeme{Token: sql.STRING, Lit: ``}},
+		{s: `'it''s'`, item: sql.Lexeme{Token: sql.STRING, Lit: `it's`}},
+		{s: `''''`, item: sql.Lexeme{Token: sql.STRING, Lit: `'`}},
+		{s: `'a"b'`, item: sql.Lexeme{Token: sql.STRING, Lit: `a"b`}},
+		{s: `'a\nb'`, item: sql.Lexeme{Token: sql.STRING, Lit: `a\nb`}},
+		{s: `E'a\nb\t\'c\\'`, item: sql.Lexeme{Token: sql.STRING, Lit: "a\nb\t'c\\"}},
+		{s: `e'\u00e9\U0001F600\q'`, item: sql.Lexeme{Token: sql.STRING, Lit: "é😀q"}},
+		{s: `E'\uZZZZ'`, item: sql.Lexeme{Token: sql.ILLEGAL, Lit: `E'\uZZZZ`}},
+		{s: `E'\x41\x4a\xg\101\7\303\251'`, item: sql.Lexeme{Token: sql.STRING, Lit: "AJxgA\aé"}},
+		{s: `E'\477'`, item: sql.Lexeme{Token: sql.ILLEGAL, Lit: `E'\477`}},
+		{s: `E'\xff'`, item: sql.Lexeme{Token: sql.ILLEGAL, Lit: `E'\xff'`}},
+		{s: `E`, item: sql.Lexeme{Token: sql.IDENT, Lit: `E`}},
+		{s: `'yolo`, item: sql.Lexeme{Token: sql.ILLEGAL, Lit: `'yolo`}},
+		{s: `'yolo"`, item: sql.Lexeme{Token: sql.ILLEGAL, Lit: `'yolo"`}},
+		{s: `E'yolo\'`, item: sql.Lexeme{Token: sql.ILLEGAL, Lit: `E'yolo\'`}},
+
+		// Quoted identifiers
+		{s: `"yolo"`, item: sql.Lexeme{Token: sql.IDENT, Lit: `yolo`}},
+		{s: `"SELECT"`, item: sql.Lexeme{Token: sql.IDENT, Lit: `SELECT`}},
+		{s: `"First Name"`, item: sql.Lexeme{Token: sql.IDENT, Lit: `First Name`}},
+		{s: `"2024_total"`, item: sql.Lexeme{Token: sql.IDENT, Lit: `2024_total`}},
+		{s: `"say ""hi"""`, item: sql.Lexeme{Token: sql.IDENT, Lit: `say "hi"`}},
+		{s: `"first name".t`, item: sql.Lexeme{Token: sql.IDENT, Lit: `first name`}},
+		{s: `"first.name"`, item: sql.Lexeme{Token: sql.IDENT, Lit: `first.name`}},
+		{s: `""`, item: sql.Lexeme{Token: sql.ILLEGAL, Lit: `""`}},
+		{s: `"yolo`, item: sql.Lexeme{Token: sql.ILLEGAL, Lit: `"yolo`}},
+		{s: "`yolo`", item: sql.Lexeme{Token: sql.ILLEGAL, Lit: "`"}},
 
 		// Comparison Operators
 		{s: `=`, item: sql.Lexeme{Token: sql.EQ, Lit: `=`}},
@@ -46,8 +75,24 @@ func TestScanner_Scan(t *testing.T) {
 		// Numerics
 		{s: `1`, item: sql.Lexeme{Token: sql.INT, Lit: `1`}},
 		{s: `0.5`, item: sql.Lexeme{Token: sql.FLOAT, Lit: `0.5`}},
-		{s: `-1.1`, item: sql.Lexeme{Token: sql.FLOAT, Lit: `-1.1`}},
-		{s: `+9`, item: sql.Lexeme{Token: sql.INT, Lit: `+9`}},
+		{s: `1.1`, item: sql.Lexeme{Token: sql.FLOAT, Lit: `1.1`}},
+		{s: `9-1`, item: sql.Lexeme{Token: sql.INT, Lit: `9`}},
+
+		// Arithmetic and string operators
+		{s: `+9`, item: sql.Lexeme{Token: sql.PLUS, Lit: `+`}},
+		{s: `-1.1`, item: sql.Lexeme{Token: sql.MINUS, Lit: `-`}},
+		{s: `/`, item: sql.Lexeme{Token: sql.SLASH, Lit: `/`}},
+		{s: `%`, item: sql.Lexeme{Token: sql.PERCENT, Lit: `%`}},
+		{s: `||`, item: sql.Lexeme{Token: sql.CONCAT, Lit: `||`}},
+		{s: `|`, item: sql.Lexeme{Token: sql.ILLEGAL, Lit: `|`}},
+
+		// Comments
+		{s: `-- comment`, item: sql.Lexeme{Token: sql.EOF}},
+		{s: "-- comment\nx", item: sql.Lexeme{Token: sql.IDENT, Lit: "x"}},
+		{s: `/* a /* nested */ comment */x`, item: sql.Lexeme{Token: sql.IDENT, Lit: "x"}},
+		{s: `/**/-`, item: sql.Lexeme{Token: sql.MINUS, Lit: "-"}},
+		{s: `/* a /* b */`, item: sql.Lexeme{Token: sql.ILLEGAL, Lit: "/* a /* b */"}},
+		{s: `/`, item: sql.Lexeme{Token: sql.SLASH, Lit: "/"}},
 
 		// Keywords
 		{s: `ON`, item: sql.Lexeme{Token: sql.ON, Lit: "ON"}},
@@ -62,6 +107,9 @@ func TestScanner_Scan(t *testing.T) {
 		{s: `SELECT`, item: sql.Lexeme{Token: sql.SELECT, Lit: "SELECT"}},
 		{s: `HAVING`, item: sql.Lexeme{Token: sql.HAVING, Lit: "HAVING"}},
 		{s: `WHERE`, item: sql.Lexeme{Token: sql.WHERE, Lit: "WHERE"}},
+		{s: `NOT`, item: sql.Lexeme{Token: sql.NOT, Lit: "NOT"}},
+		{s: `is`, item: sql.Lexeme{Token: sql.IS, Lit: "is"}},
+		{s: `NULL`, item: sql.Lexeme{Token: sql.NULLLIT, Lit: "NULL"}},
 	}
 
 	for i, tt := range tests {
@@ -78,6 +126,7 @@ func TestScanner_Scan(t *testing.T) {
 }
 
 func TestScanner_Scan_Sequence(t *testing.T) {
+	pos := func(offset, line, column int) sql.Pos { return sql.Pos{Offset: offset, Line: line, Column: column} }
 	var tests = []struct {
 		s     string
 		items []sql.Lexeme
@@ -85,44 +134,94 @@ func TestScanner_Scan_Sequence(t *testing.T) {
 		{
 			s: "SELECT *\nFROM yolo",
 			items: []sql.Lexeme{
-				{Token: sql.SELECT, Lit: "SELECT"},
-				{Token: sql.ASTERISK, Lit: "*"},
-				{Token: sql.FROM, Lit: "FROM"},
-				{Token: sql.IDENT, Lit: "yolo"},
-				{Token: sql.EOF, Lit: ""},
+				{Token: sql.SELECT, Lit: "SELECT", Pos: pos(0, 1, 1)},
+				{Token: sql.ASTERISK, Lit: "*", Pos: pos(7, 1, 8)},
+				{Token: sql.FROM, Lit: "FROM", Pos: pos(9, 2, 1)},
+				{Token: sql.IDENT, Lit: "yolo", Pos: pos(14, 2, 6)},
+				{Token: sql.EOF, Lit: "", Pos: pos(18, 2, 10)},
 			},
 		},
 		{
 			s: "SELECT *\nFROM yolo WHERE iam",
 			items: []sql.Lexeme{
-				{Token: sql.SELECT, Lit: "SELECT"},
-				{Token: sql.ASTERISK, Lit: "*"},
-				{Token: sql.FROM, Lit: "FROM"},
-				{Token: sql.IDENT, Lit: "yolo"},
-				{Token: sql.WHERE, Lit: "WHERE"},
-				{Token: sql.IDENT, Lit: "iam"},
-				{Token: sql.EOF, Lit: ""},
+				{Token: sql.SELECT, Lit: "SELECT", Pos: pos(0, 1, 1)},
+				{Token: sql.ASTERISK, Lit: "*", Pos: pos(7, 1, 8)},
+				{Token: sql.FROM, Lit: "FROM", Pos: pos(9, 2, 1)},
+				{Token: sql.IDENT, Lit: "yolo", Pos: pos(14, 2, 6)},
+				{Token: sql.WHERE, Lit: "WHERE", Pos: pos(19, 2, 11)},
+				{Token: sql.IDENT, Lit: "iam", Pos: pos(25, 2, 17)},
+				{Token: sql.EOF, Lit: "", Pos: pos(28, 2, 20)},
 			},
 		},
 		{
 			s: "SELECT *\nFROM yolo JOIN wow ON yolo.iam = wow.you_are",
 			items: []sql.Lexeme{
-				{Token: sql.SELECT, Lit: "SELECT"},
-				{Token: sql.ASTERISK, Lit: "*"},
-				{Token: sql.FROM, Lit: "FROM"},
-				{Token: sql.IDENT, Lit: "yolo"},
-				{Token: sql.JOIN, Lit: "JOIN"},
-				{Token: sql.IDENT, Lit: "wow"},
-				{Token: sql.ON, Lit: "ON"},
-				{Token: sql.IDENT, Lit: "yolo.iam"},
-				{Token: sql.EQ, Lit: "="},
-				{Token: sql.IDENT, Lit: "wow.you_are"},
-				{Token: sql.EOF, Lit: ""},
+				{Token: sql.SELECT, Lit: "SELECT", Pos: pos(0, 1, 1)},
+				{Token: sql.ASTERISK, Lit: "*", Pos: pos(7, 1, 8)},
+				{Token: sql.FROM, Lit: "FROM", Pos: pos(9, 2, 1)},
+				{Token: sql.IDENT, Lit: "yolo", Pos: pos(14, 2, 6)},
+				{Token: sql.JOIN, Lit: "JOIN", Pos: pos(19, 2, 11)},
+				{Token: sql.IDENT, Lit: "wow", Pos: pos(24, 2, 16)},
+				{Token: sql.ON, Lit: "ON", Pos: pos(28, 2, 20)},
+				{Token: sql.IDENT, Lit: "yolo", Pos: pos(31, 2, 23)},
+				{Token: sql.DOT, Lit: ".", Pos: pos(35, 2, 27)},
+				{Token: sql.IDENT, Lit: "iam", Pos: pos(36, 2, 28)},
+				{Token: sql.EQ, Lit: "=", Pos: pos(40, 2, 32)},
+				{Token: sql.IDENT, Lit: "wow", Pos: pos(42, 2, 34)},
+				{Token: sql.DOT, Lit: ".", Pos: pos(45, 2, 37)},
+				{Token: sql.IDENT, Lit: "you_are", Pos: pos(46, 2, 38)},
+				{Token: sql.EOF, Lit: "", Pos: pos(53, 2, 45)},
+			},
+		},
+		{
+			s: `"my db".s."my table".x`,
+			items: []sql.Lexeme{
+				{Token: sql.IDENT, Lit: "my db", Pos: pos(0, 1, 1)},
+				{Token: sql.DOT, Lit: ".", Pos: pos(7, 1, 8)},
+				{Token: sql.IDENT, Lit: "s", Pos: pos(8, 1, 9)},
+				{Token: sql.DOT, Lit: ".", Pos: pos(9, 1, 10)},
+				{Token: sql.IDENT, Lit: "my table", Pos: pos(10, 1, 11)},
+				{Token: sql.DOT, Lit: ".", Pos: pos(20, 1, 21)},
+				{Token: sql.IDENT, Lit: "x", Pos: pos(21, 1, 22)},
+				{Token: sql.EOF, Lit: "", Pos: pos(22, 1, 23)},
 			},
 		},
 		{
 			s:     "",
-			items: []sql.Lexeme{{Token: sql.EOF, Lit: ""}},
+			items: []sql.Lexeme{{Token: sql.EOF, Lit: "", Pos: pos(0, 1, 1)}},
+		},
+		{
+			s: "a <> 'é'\n\tb",
+			items: []sql.Lexeme{
+				{Token: sql.IDENT, Lit: "a", Pos: pos(0, 1, 1)},
+				{Token: sql.NEQ, Lit: "<>", Pos: pos(2, 1, 3)},
+				{Token: sql.STRING, Lit: "é", Pos: pos(5, 1, 6)},
+				{Token: sql.IDENT, Lit: "b", Pos: pos(11, 2, 2)},
+				{Token: sql.EOF, Lit: "", Pos: pos(12, 2, 3)},
+			},
+		},
+		{
+			s: "-- header\nSELECT a /* b */, c--d\n",
+			items: []sql.Lexeme{
+				{Token: sql.SELECT, Lit: "SELECT", Pos: pos(10, 2, 1)},
+				{Token: sql.IDENT, Lit: "a", Pos: pos(17, 2, 8)},
+				{Token: sql.COMMA, Lit: ",", Pos: pos(26, 2, 17)},
+				{Token: sql.IDENT, Lit: "c", Pos: pos(28, 2, 19)},
+				{Token: sql.EOF, Lit: "", Pos: pos(33, 3, 1)},
+			},
+		},
+		{
+			s: "price*qty-1||'x'",
+			items: []sql.Lexeme{
+				{Token: sql.IDENT, Lit: "price", Pos: pos(0, 1, 1)},
+				{Token: sql.ASTERISK, Lit: "*", Pos: pos(5, 1, 6)},
+				{Token: sql.IDENT, Lit: "qty", Pos: pos(6, 1, 7)},
+				{Token: sql.MINUS, Lit: "-", Pos: pos(9, 1, 10)},
+				{Token: sql.INT, Lit: "1", Pos: pos(10, 1, 11)},
+				{Token: sql.CONCAT, Lit: "||", Pos: pos(11, 1, 12)},
+				{Token: sql.STRING, Lit: "x", Pos: pos(13, 1, 14)},
+				{Token: sql.EOF, Lit: "", Pos: pos(16, 1, 17)},
+			},
 		},
 	}
 
@@ -144,6 +243,48 @@ func TestScanner_Scan_Sequence(t *testing.T) {
 	}
 }
 
+func TestScanner_Scan_Comments(t *testing.T) {
+	s := sql.NewScanner(strings.NewReader("a -- line\n/* block /* nested */ */ b /* unterminated"))
+	s.Comments = true
+
+	want := []sql.Lexeme{
+		{Token: sql.IDENT, Lit: "a", Pos: sql.Pos{Offset: 0, Line: 1, Column: 1}},
+		{Token: sql.COMMENT, Lit: "-- line", Pos: sql.Pos{Offset: 2, Line: 1, Column: 3}},
+		{Token: sql.COMMENT, Lit: "/* block /* nested */ */", Pos: sql.Pos{Offset: 10, Line: 2, Column: 1}},
+		{Token: sql.IDENT, Lit: "b", Pos: sql.Pos{Offset: 35, Line: 2, Column: 26}},
+		{Token: sql.ILLEGAL, Lit: "/* unterminated", Pos: sql.Pos{Offset: 37, Line: 2, Column: 28}},
+	}
+	for i, w := range want {
+		if got := s.Scan(); got != w {
+			t.Errorf("%d. Scan() = %+v, want %+v", i, got, w)
+		}
+	}
+	if s.Err() == nil {
+		t.Errorf("Err() expected error for unterminated comment")
+	}
+	if got := s.Scan(); got.Token != sql.EOF || s.Err() != nil {
+		t.Errorf("Scan() = %+v, %v, want EOF", got, s.Err())
+	}
+}
+
+func TestScanner_Scan_Backticks(t *testing.T) {
+	s := sql.NewScanner(strings.NewReader("`order` `a``b`.\"c\" `x"))
+	s.Backticks = true
+
+	want := []sql.Lexeme{
+		{Token: sql.IDENT, Lit: "order", Pos: sql.Pos{Offset: 0, Line: 1, Column: 1}},
+		{Token: sql.IDENT, Lit: "a`b", Pos: sql.Pos{Offset: 8, Line: 1, Column: 9}},
+		{Token: sql.DOT, Lit: ".", Pos: sql.Pos{Offset: 14, Line: 1, Column: 15}},
+		{Token: sql.IDENT, Lit: "c", Pos: sql.Pos{Offset: 15, Line: 1, Column: 16}},
+		{Token: sql.ILLEGAL, Lit: "`x", Pos: sql.Pos{Offset: 19, Line: 1, Column: 20}},
+	}
+	for i, w := range want {
+		if got := s.Scan(); got != w {
+			t.Errorf("%d. Scan() = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
 // var benchRes sql.Lexeme
 
 func BenchmarkScanner_Scan(b *testing.B) {
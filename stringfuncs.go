@@ -0,0 +1,162 @@
+package sql
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// stringFunctions operate on TEXT values character by character, a character being a Unicode code point.
+// Positions are counted from 1, as in the rest of SQL.
+var stringFunctions = []Function{
+	{Name: "UPPER", Args: []DataType{TEXT}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return strings.ToUpper(args[0].(string)), nil
+	}},
+	{Name: "LOWER", Args: []DataType{TEXT}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return strings.ToLower(args[0].(string)), nil
+	}},
+	{Name: "LENGTH", Args: []DataType{TEXT}, Returns: INTEGER, Call: func(args []Value) (Value, error) {
+		return int64(utf8.RuneCountInString(args[0].(string))), nil
+	}},
+	{Name: "SUBSTR", Args: []DataType{TEXT, INTEGER}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return substr([]rune(args[0].(string)), args[1].(int64), math.MaxInt64), nil
+	}},
+	{Name: "SUBSTR", Args: []DataType{TEXT, INTEGER, INTEGER}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		n := args[2].(int64)
+		if n < 0 {
+			return nil, errors.New("SUBSTR length must not be negative")
+		}
+		return substr([]rune(args[0].(string)), args[1].(int64), n), nil
+	}},
+	{Name: "TRIM", Args: []DataType{TEXT}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return strings.Trim(args[0].(string), " "), nil
+	}},
+	{Name: "TRIM", Args: []DataType{TEXT, TEXT}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return strings.Trim(args[0].(string), args[1].(string)), nil
+	}},
+	{Name: "LTRIM", Args: []DataType{TEXT}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return strings.TrimLeft(args[0].(string), " "), nil
+	}},
+	{Name: "LTRIM", Args: []DataType{TEXT, TEXT}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return strings.TrimLeft(args[0].(string), args[1].(string)), nil
+	}},
+	{Name: "RTRIM", Args: []DataType{TEXT}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return strings.TrimRight(args[0].(string), " "), nil
+	}},
+	{Name: "RTRIM", Args: []DataType{TEXT, TEXT}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return strings.TrimRight(args[0].(string), args[1].(string)), nil
+	}},
+	{Name: "REPLACE", Args: []DataType{TEXT, TEXT, TEXT}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		s, from := args[0].(string), args[1].(string)
+		if from == "" {
+			return s, nil
+		}
+		return strings.ReplaceAll(s, from, args[2].(string)), nil
+	}},
+	{Name: "INSTR", Args: []DataType{TEXT, TEXT}, Returns: INTEGER, Call: func(args []Value) (Value, error) {
+		s := args[0].(string)
+		i := strings.Index(s, args[1].(string))
+		if i < 0 {
+			return int64(0), nil
+		}
+		return int64(utf8.RuneCountInString(s[:i]) + 1), nil
+	}},
+	{Name: "CONCAT", Args: []DataType{TEXT}, Variadic: true, Returns: TEXT, CalledOnNull: true, Call: func(args []Value) (Value, error) {
+		var sb strings.Builder
+		for _, v := range args {
+			if v != nil {
+				sb.WriteString(v.(string))
+			}
+		}
+		return sb.String(), nil
+	}},
+	{Name: "LPAD", Args: []DataType{TEXT, INTEGER}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return pad(args[0].(string), args[1].(int64), " ", true)
+	}},
+	{Name: "LPAD", Args: []DataType{TEXT, INTEGER, TEXT}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return pad(args[0].(string), args[1].(int64), args[2].(string), true)
+	}},
+	{Name: "RPAD", Args: []DataType{TEXT, INTEGER}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return pad(args[0].(string), args[1].(int64), " ", false)
+	}},
+	{Name: "RPAD", Args: []DataType{TEXT, INTEGER, TEXT}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return pad(args[0].(string), args[1].(int64), args[2].(string), false)
+	}},
+	{Name: "SPLIT_PART", Args: []DataType{TEXT, TEXT, INTEGER}, Returns: TEXT, Call: func(args []Value) (Value, error) {
+		return splitPart(args[0].(string), args[1].(string), args[2].(int64))
+	}},
+}
+
+func init() {
+	registerBuiltins(stringFunctions)
+}
+
+// substr extracts the n characters starting at the position start.
+// The characters before the first one count in n when start is lower than 1.
+func substr(s []rune, start, n int64) string {
+	end := start + n
+	if end < start {
+		// n is so large that the end overflows, the substring goes to the end of s.
+		end = int64(len(s)) + 1
+	}
+	if start < 1 {
+		start = 1
+	}
+	if end > int64(len(s))+1 {
+		end = int64(len(s)) + 1
+	}
+	if start >= end {
+		return ""
+	}
+	return string(s[start-1 : end-1])
+}
+
+// maxPadLength bounds the length of the strings built by LPAD and RPAD.
+const maxPadLength = 1 << 20
+
+// pad fills s up to n characters with fill, on the left when left is set, otherwise on the right.
+// A string longer than n characters is truncated to its first n characters.
+func pad(s string, n int64, fill string, left bool) (Value, error) {
+	if n > maxPadLength {
+		return nil, errors.New("padded length is too large")
+	}
+	str := []rune(s)
+	if n <= 0 {
+		return "", nil
+	}
+	if int64(len(str)) >= n || fill == "" {
+		if int64(len(str)) > n {
+			str = str[:n]
+		}
+		return string(str), nil
+	}
+
+	padding := make([]rune, 0, n-int64(len(str)))
+	for f := []rune(fill); int64(len(padding)) < n-int64(len(str)); {
+		padding = append(padding, f[len(padding)%len(f)])
+	}
+	if left {
+		return string(padding) + s, nil
+	}
+	return s + string(padding), nil
+}
+
+// splitPart returns the n-th field of s split on the delimiter, the fields are counted from the end when n is negative.
+// An empty string is returned when there are fewer than n fields.
+func splitPart(s, delim string, n int64) (Value, error) {
+	if n == 0 {
+		return nil, errors.New("SPLIT_PART field position must not be zero")
+	}
+	fields := []string{s}
+	if delim != "" {
+		fields = strings.Split(s, delim)
+	}
+	if n < 0 {
+		n += int64(len(fields)) + 1
+	}
+	if n < 1 || n > int64(len(fields)) {
+		return "", nil
+	}
+	return fields[n-1], nil
+}
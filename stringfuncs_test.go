@@ -0,0 +1,71 @@
+package sql_test
+
+import (
+	"reflect"
+	"testing"
+
+	sql "github.com/ndilsou/go-rdbms-playground"
+)
+
+func TestStringFunctions(t *testing.T) {
+	cols := []sql.ResultColumn{{Table: "t", Name: "s", Type: sql.TEXT}, {Table: "t", Name: "n", Type: sql.TEXT}}
+	row := sql.Row{"Ünïcödé", nil}
+
+	str := func(v string) sql.Expr { return &sql.BasicLit{Kind: sql.STRING, Value: v} }
+	num := func(v string) sql.Expr { return &sql.BasicLit{Kind: sql.INT, Value: v} }
+	call := func(name string, args ...sql.Expr) sql.Expr { return &sql.CallExpr{Name: name, Args: args} }
+	s, n := &sql.QualifiedName{Column: "s"}, &sql.QualifiedName{Column: "n"}
+
+	tests := []struct {
+		name    string
+		expr    sql.Expr
+		want    sql.Value
+		wantErr bool
+	}{
+		{name: "upper", expr: call("UPPER", s), want: "ÜNÏCÖDÉ"},
+		{name: "lower", expr: call("lower", s), want: "ünïcödé"},
+		{name: "length", expr: call("LENGTH", s), want: int64(7)},
+		{name: "length of null", expr: call("LENGTH", n), want: nil},
+		{name: "substr", expr: call("SUBSTR", s, num("3")), want: "ïcödé"},
+		{name: "substr from zero", expr: call("SUBSTR", s, num("0")), want: "Ünïcödé"},
+		{name: "substr from before start", expr: call("SUBSTR", s, num("-2")), want: "Ünïcödé"},
+		{name: "substr with length", expr: call("SUBSTR", s, num("2"), num("3")), want: "nïc"},
+		{name: "substr before start", expr: call("SUBSTR", s, num("-1"), num("3")), want: "Ü"},
+		{name: "substr after end", expr: call("SUBSTR", s, num("10")), want: ""},
+		{name: "substr negative length", expr: call("SUBSTR", s, num("1"), num("-1")), wantErr: true},
+		{name: "trim", expr: call("TRIM", str("  a b  ")), want: "a b"},
+		{name: "trim characters", expr: call("TRIM", str("xyaxy"), str("yx")), want: "a"},
+		{name: "ltrim", expr: call("LTRIM", str("  a  ")), want: "a  "},
+		{name: "rtrim characters", expr: call("RTRIM", s, str("éd")), want: "Ünïcö"},
+		{name: "replace", expr: call("REPLACE", s, str("ö"), str("o")), want: "Ünïcodé"},
+		{name: "replace empty", expr: call("REPLACE", s, str(""), str("o")), want: "Ünïcödé"},
+		{name: "replace null", expr: call("REPLACE", s, str("ö"), n), want: nil},
+		{name: "instr", expr: call("INSTR", s, str("cö")), want: int64(4)},
+		{name: "instr not found", expr: call("INSTR", s, str("x")), want: int64(0)},
+		{name: "concat", expr: call("CONCAT", s, n, str("!")), want: "Ünïcödé!"},
+		{name: "concat of integer", expr: call("CONCAT", s, num("1")), wantErr: true},
+		{name: "lpad", expr: call("LPAD", s, num("9"), str("ab")), want: "abÜnïcödé"},
+		{name: "lpad spaces", expr: call("LPAD", str("a"), num("3")), want: "  a"},
+		{name: "lpad truncates", expr: call("LPAD", s, num("3"), str("ab")), want: "Ünï"},
+		{name: "rpad", expr: call("RPAD", s, num("10"), str("ab")), want: "Ünïcödéaba"},
+		{name: "rpad empty fill", expr: call("RPAD", s, num("10"), str("")), want: "Ünïcödé"},
+		{name: "rpad too long", expr: call("RPAD", s, num("100000000"), str("a")), wantErr: true},
+		{name: "split part", expr: call("SPLIT_PART", str("a,b,,c"), str(","), num("2")), want: "b"},
+		{name: "split part from end", expr: call("SPLIT_PART", str("a,b,,c"), str(","), num("-1")), want: "c"},
+		{name: "split part out of range", expr: call("SPLIT_PART", str("a,b,,c"), str(","), num("5")), want: ""},
+		{name: "split part zero", expr: call("SPLIT_PART", str("a,b"), str(","), num("0")), wantErr: true},
+		{name: "wrong argument type", expr: call("UPPER", num("1")), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := eval(tt.expr, cols, row)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Eval() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eval() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,11 +7,16 @@ const (
 	EOF Token = iota + 1
 	ILLEGAL
 	WS
+	COMMENT // -- line or /* block */
 
 	misc_begin
 	// Misc characters
 	COMMA
 	SEMICOLON
+	LPAREN
+	RPAREN
+	TYPECAST // ::
+	DOT      // . between the parts of a qualified name
 
 	misc_end
 
@@ -23,6 +28,9 @@ const (
 	FLOAT
 	INT
 	STRING
+	NULLLIT   // NULL
+	DATE      // DATE '2024-01-01'
+	TIMESTAMP // TIMESTAMP '2024-01-01 12:00:00'
 
 	literal_end
 
@@ -37,27 +45,49 @@ const (
 
 	operator_end
 
+	arithmetic_begin
+	// Arithmetic and string operators, ASTERISK doubles as the multiplication operator
+	PLUS    // +
+	MINUS   // -
+	SLASH   // /
+	PERCENT // %
+	CONCAT  // ||
+
+	arithmetic_end
+
 	keyword_begin
 	// Keywords
 	AND
 	AS
+	BETWEEN
 	BY
+	CASE
+	CAST
 	DISTINCT
+	ELSE
+	END
+	ESCAPE
 	FROM
 	GROUP
 	HAVING
+	IN
 	INNER
+	IS
 	JOIN
 	LEFT
 	RIGHT
 	FULL
+	LIKE
 	LIMIT
+	NOT
 	OFFSET
 	ON
 	OR
 	ORDER
 	OUTER
 	SELECT
+	THEN
+	WHEN
 	WHERE
 
 	keyword_end
@@ -67,10 +97,19 @@ var tokens = map[Token]string{
 	AND:       "AND",
 	AS:        "AS",
 	ASTERISK:  "ASTERISK",
+	BETWEEN:   "BETWEEN",
+	CASE:      "CASE",
+	CAST:      "CAST",
 	COMMA:     "COMMA",
+	COMMENT:   "COMMENT",
+	DOT:       "DOT",
+	CONCAT:    "CONCAT",
 	DISTINCT:  "DISTINCT",
+	ELSE:      "ELSE",
+	END:       "END",
 	EOF:       "EOF",
 	EQ:        "EQ",
+	ESCAPE:    "ESCAPE",
 	FLOAT:     "FLOAT",
 	FROM:      "FROM",
 	GROUP:     "GROUP BY",
@@ -79,44 +118,91 @@ var tokens = map[Token]string{
 	HAVING:    "HAVING",
 	IDENT:     "IDENT",
 	ILLEGAL:   "ILLEGAL",
+	IN:        "IN",
 	INNER:     "INNER",
 	INT:       "INT",
+	IS:        "IS",
 	JOIN:      "JOIN",
 	LEFT:      "LEFT",
+	LIKE:      "LIKE",
 	LIMIT:     "LIMIT",
+	LPAREN:    "LPAREN",
 	LT:        "LT",
 	LTE:       "LTE",
+	MINUS:     "MINUS",
 	NEQ:       "NEQ",
+	NOT:       "NOT",
+	NULLLIT:   "NULL",
+	DATE:      "DATE",
+	TIMESTAMP: "TIMESTAMP",
 	OFFSET:    "OFFSET",
 	ON:        "ON",
 	OR:        "OR",
 	ORDER:     "ORDER BY",
 	OUTER:     "OUTER",
+	PERCENT:   "PERCENT",
+	PLUS:      "PLUS",
 	SELECT:    "SELECT",
 	SEMICOLON: "SEMICOLON",
+	SLASH:     "SLASH",
 	STRING:    "STRING",
+	THEN:      "THEN",
+	WHEN:      "WHEN",
 	WHERE:     "WHERE",
 	WS:        "WS",
 	RIGHT:     "RIGHT",
+	RPAREN:    "RPAREN",
+	TYPECAST:  "TYPECAST",
 	FULL:      "FULL",
 }
+
+// symbols holds the notation of the operators written with symbols.
+var symbols = map[Token]string{
+	EQ:       "=",
+	NEQ:      "<>",
+	LT:       "<",
+	LTE:      "<=",
+	GT:       ">",
+	GTE:      ">=",
+	PLUS:     "+",
+	MINUS:    "-",
+	ASTERISK: "*",
+	SLASH:    "/",
+	PERCENT:  "%",
+	CONCAT:   "||",
+}
+
 var keywords = map[string]Token{
 	"AND":      AND,
+	"AS":       AS,
+	"BETWEEN":  BETWEEN,
 	"BY":       BY,
+	"CASE":     CASE,
+	"CAST":     CAST,
 	"DISTINCT": DISTINCT,
+	"ELSE":     ELSE,
+	"END":      END,
+	"ESCAPE":   ESCAPE,
 	"FROM":     FROM,
 	"GROUP":    GROUP,
 	"HAVING":   HAVING,
+	"IN":       IN,
 	"INNER":    INNER,
+	"IS":       IS,
 	"JOIN":     JOIN,
 	"LEFT":     LEFT,
+	"LIKE":     LIKE,
 	"LIMIT":    LIMIT,
+	"NOT":      NOT,
+	"NULL":     NULLLIT,
 	"OFFSET":   OFFSET,
 	"ON":       ON,
 	"OR":       OR,
 	"ORDER":    ORDER,
 	"OUTER":    OUTER,
 	"SELECT":   SELECT,
+	"THEN":     THEN,
+	"WHEN":     WHEN,
 	"WHERE":    WHERE,
 	"RIGHT":    RIGHT,
 	"FULL":     FULL,
@@ -134,8 +220,58 @@ func (t Token) IsMisc() bool { return t >= misc_begin && t <= misc_end }
 
 func (t Token) IsComparisonOperator() bool { return t >= operator_begin && t <= operator_end }
 
+func (t Token) IsArithmeticOperator() bool {
+	return t == ASTERISK || (t >= arithmetic_begin && t <= arithmetic_end)
+}
+
 func (t Token) IsLiteral() bool { return t >= literal_begin && t <= literal_end }
 
 func (t Token) IsKeyword() bool { return t >= keyword_begin && t <= keyword_end }
 
 func (t Token) IsTerminal() bool { return t == EOF || t == SEMICOLON }
+
+// LowestPrec is the precedence of the tokens which are not binary operators.
+const LowestPrec = 0
+
+// Precedence returns the precedence of a binary operator, operators of higher precedence bind tighter.
+func (t Token) Precedence() int {
+	switch {
+	case t == OR:
+		return 1
+	case t == AND:
+		return 2
+	case t == IS:
+		return 4
+	case t.IsComparisonOperator():
+		return 5
+	case t == IN || t == BETWEEN || t == LIKE:
+		return 6
+	case t == CONCAT:
+		return 7
+	case t == PLUS || t == MINUS:
+		return 8
+	case t == ASTERISK || t == SLASH || t == PERCENT:
+		return 9
+	}
+	return LowestPrec
+}
+
+// UnaryPrecedence returns the precedence of a prefix operator, NOT binds looser than the comparisons
+// while signs bind tighter than any binary operator.
+func (t Token) UnaryPrecedence() int {
+	switch t {
+	case NOT:
+		return 3
+	case PLUS, MINUS:
+		return 10
+	}
+	return LowestPrec
+}
+
+// Symbol returns the notation of an operator in a statement.
+func (t Token) Symbol() string {
+	if s, ok := symbols[t]; ok {
+		return s
+	}
+	return t.String()
+}
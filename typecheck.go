@@ -0,0 +1,429 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TypeInfo records the types inferred for the expressions of a statement.
+type TypeInfo struct {
+	// Types maps every expression of the statement to its type. NULL is the type of the NULL literal,
+	// and of the expressions whose type cannot be known before execution.
+	Types map[Expr]DataType
+}
+
+// TypeOf returns the type of an expression of the statement, NULL when it is unknown.
+func (info *TypeInfo) TypeOf(expr Expr) DataType {
+	return info.Types[expr]
+}
+
+// Check plans the statement and infers the types of all of its expressions.
+// It fails on the operations which are invalid for the types of their operands.
+func (p Planner) Check(stmt Stmt) (*TypeInfo, error) {
+	plan, err := p.Plan(stmt)
+	if err != nil {
+		return nil, err
+	}
+	info := TypeInfo{Types: make(map[Expr]DataType)}
+	if _, err := planColumns(p.c, plan, info.Types); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Columns describes the rows produced by a plan, the names and types of their columns, without executing it.
+func (p Planner) Columns(plan PlanNode) ([]ResultColumn, error) {
+	return planColumns(p.c, plan, nil)
+}
+
+// planColumns computes the columns produced by a plan, and records the types of the expressions
+// of its nodes in types when not nil.
+func planColumns(c Catalog, plan PlanNode, types map[Expr]DataType) ([]ResultColumn, error) {
+	switch n := plan.(type) {
+	case *TableScanNode:
+		r, err := c.GetRelation(n.relation())
+		if err != nil {
+			return nil, err
+		}
+		cols := make([]ResultColumn, 0, len(r.Columns))
+		for _, col := range r.Columns {
+			cols = append(cols, ResultColumn{Table: n.name(), Name: col.Name, Type: col.Type})
+		}
+		return cols, nil
+	case *FilterNode:
+		cols, err := planColumns(c, n.From, types)
+		if err != nil {
+			return nil, err
+		}
+		return cols, checkPredicate(n.Filter, cols, types)
+	case *ProjectionNode:
+		in, err := planColumns(c, n.From, types)
+		if err != nil {
+			return nil, err
+		}
+		var cols []ResultColumn
+		for _, expr := range n.Columns {
+			if n, ok := expr.(*QualifiedName); ok && n.Column == "*" {
+				pos, err := starColumns(n, in)
+				if err != nil {
+					return nil, err
+				}
+				for _, i := range pos {
+					cols = append(cols, in[i])
+				}
+				continue
+			}
+			col, err := resultColumn(expr, in, types)
+			if err != nil {
+				return nil, err
+			}
+			cols = append(cols, col)
+		}
+		return cols, nil
+	case *SortNode:
+		cols, err := planColumns(c, n.From, types)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range n.Keys {
+			if _, err := checkExpr(k, cols, types); err != nil {
+				return nil, err
+			}
+		}
+		return cols, nil
+	case *LimitNode:
+		return planColumns(c, n.From, types)
+	case *OffsetNode:
+		return planColumns(c, n.From, types)
+	case *HashDistinctNode:
+		return planColumns(c, n.From, types)
+	case *SortedDistinctNode:
+		return planColumns(c, n.From, types)
+	case *NestedLoopNode:
+		return joinPlanColumns(c, n.Outer, n.Inner, n.Criterion, types)
+	case *HashJoinNode:
+		return joinPlanColumns(c, n.Outer, n.Inner, n.Criterion, types)
+	case *MergeJoinNode:
+		return joinPlanColumns(c, n.Outer, n.Inner, n.Criterion, types)
+	case *HashAggregateNode:
+		return aggregatePlanColumns(c, n.Groups, n.Aggregates, n.From, types)
+	case *GroupAggregateNode:
+		return aggregatePlanColumns(c, n.Groups, n.Aggregates, n.From, types)
+	case nil:
+		return nil, errors.New("invalid plan: missing node")
+	default:
+		return nil, fmt.Errorf("unsupported plan node %T", plan)
+	}
+}
+
+func joinPlanColumns(c Catalog, outer, inner PlanNode, criterion Expr, types map[Expr]DataType) ([]ResultColumn, error) {
+	outerCols, err := planColumns(c, outer, types)
+	if err != nil {
+		return nil, err
+	}
+	innerCols, err := planColumns(c, inner, types)
+	if err != nil {
+		return nil, err
+	}
+	cols := joinColumns(outerCols, innerCols)
+	return cols, checkPredicate(criterion, cols, types)
+}
+
+func aggregatePlanColumns(c Catalog, groups []*QualifiedName, aggregates []*AggregateExpr, from PlanNode, types map[Expr]DataType) ([]ResultColumn, error) {
+	in, err := planColumns(c, from, types)
+	if err != nil {
+		return nil, err
+	}
+	return aggregateColumns(groups, aggregates, in, types)
+}
+
+// aggregateColumns describes the rows of an aggregate operator: the grouped columns followed by
+// a column for each aggregate, named after it.
+func aggregateColumns(groups []*QualifiedName, aggregates []*AggregateExpr, in []ResultColumn, types map[Expr]DataType) ([]ResultColumn, error) {
+	var cols []ResultColumn
+	for _, g := range groups {
+		col, err := resultColumn(g, in, types)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	for _, a := range aggregates {
+		t, err := checkExpr(a, in, types)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, ResultColumn{Name: a.String(), Type: t})
+	}
+	return cols, nil
+}
+
+// resultColumn describes the column computed by an expression of a select list.
+func resultColumn(expr Expr, cols []ResultColumn, types map[Expr]DataType) (ResultColumn, error) {
+	t, err := checkExpr(expr, cols, types)
+	if err != nil {
+		return ResultColumn{}, err
+	}
+	switch e := expr.(type) {
+	case *QualifiedName:
+		i, err := resolveColumn(cols, e)
+		if err != nil {
+			return ResultColumn{}, err
+		}
+		return cols[i], nil
+	case *AggregateExpr:
+		if i, err := aggregateColumn(cols, e); err == nil {
+			return cols[i], nil
+		}
+	case *AliasExpr:
+		return ResultColumn{Name: e.Alias.Name, Type: t}, nil
+	}
+	return ResultColumn{Name: fmt.Sprint(expr), Type: t}, nil
+}
+
+// checkPredicate ensures a predicate is a valid BOOLEAN expression.
+func checkPredicate(expr Expr, cols []ResultColumn, types map[Expr]DataType) error {
+	t, err := checkExpr(expr, cols, types)
+	if err != nil {
+		return err
+	}
+	if t != BOOLEAN && t != NULL {
+		return fmt.Errorf("predicate %s must be BOOLEAN, not %s", expr, t)
+	}
+	return nil
+}
+
+// checkExpr infers the type of an expression from the types of the columns it reads, and rejects
+// the operations which are invalid for the types of their operands. NULL operands are accepted
+// by every operation. The types of the expression and of its sub-expressions are recorded in types when not nil.
+func checkExpr(expr Expr, cols []ResultColumn, types map[Expr]DataType) (DataType, error) {
+	c := checker{cols: cols, types: types}
+	return c.check(expr)
+}
+
+type checker struct {
+	cols  []ResultColumn
+	types map[Expr]DataType
+}
+
+func (c checker) check(expr Expr) (DataType, error) {
+	t, err := c.infer(expr)
+	if err != nil {
+		return NULL, err
+	}
+	if c.types != nil {
+		c.types[expr] = t
+	}
+	return t, nil
+}
+
+// checkAll checks a list of expressions, the nil ones being skipped.
+func (c checker) checkAll(exprs ...Expr) ([]DataType, error) {
+	types := make([]DataType, len(exprs))
+	for i, e := range exprs {
+		if e == nil {
+			continue
+		}
+		t, err := c.check(e)
+		if err != nil {
+			return nil, err
+		}
+		types[i] = t
+	}
+	return types, nil
+}
+
+func (c checker) infer(expr Expr) (DataType, error) {
+	switch e := expr.(type) {
+	case *QualifiedName:
+		i, err := resolveColumn(c.cols, e)
+		if err != nil {
+			return NULL, err
+		}
+		return c.cols[i].Type, nil
+	case *BasicLit:
+		switch e.Kind {
+		case INT:
+			return INTEGER, nil
+		case FLOAT:
+			return REAL, nil
+		case STRING:
+			return TEXT, nil
+		case DATE, TIMESTAMP:
+			return DATETIME, nil
+		}
+		return NULL, nil
+	case *AggregateExpr:
+		return c.inferAggregate(e)
+	case *UnaryExpr:
+		t, err := c.check(e.X)
+		if err != nil {
+			return NULL, err
+		}
+		if e.Op == NOT {
+			return BOOLEAN, expectBoolean(e.Op, t)
+		}
+		if t != NULL && numericType(t, t) == NULL {
+			return NULL, fmt.Errorf("cannot apply %s to %s", e.Op.Symbol(), t)
+		}
+		return t, nil
+	case *BinaryExpr:
+		return c.inferBinary(e)
+	case *IsNullExpr:
+		_, err := c.check(e.X)
+		return BOOLEAN, err
+	case *InExpr:
+		ts, err := c.checkAll(append([]Expr{e.X}, e.List...)...)
+		if err != nil {
+			return NULL, err
+		}
+		for _, t := range ts[1:] {
+			if _, err := comparisonType(ts[0], t); err != nil {
+				return NULL, err
+			}
+		}
+		return BOOLEAN, nil
+	case *BetweenExpr:
+		ts, err := c.checkAll(e.X, e.Low, e.High)
+		if err != nil {
+			return NULL, err
+		}
+		for _, t := range ts[1:] {
+			if _, err := comparisonType(ts[0], t); err != nil {
+				return NULL, err
+			}
+		}
+		return BOOLEAN, nil
+	case *LikeExpr:
+		ts, err := c.checkAll(e.X, e.Pattern, e.Escape)
+		if err != nil {
+			return NULL, err
+		}
+		for _, t := range ts {
+			if t != TEXT && t != NULL {
+				return NULL, fmt.Errorf("LIKE expects TEXT operands, got %s", t)
+			}
+		}
+		return BOOLEAN, nil
+	case *CaseExpr:
+		return c.inferCase(e)
+	case *CastExpr:
+		t, err := c.check(e.X)
+		if err != nil {
+			return NULL, err
+		}
+		if !canCast(t, e.Type) {
+			return NULL, fmt.Errorf("cannot cast %s to %s", t, e.Type)
+		}
+		return e.Type, nil
+	case *CallExpr:
+		ts, err := c.checkAll(e.Args...)
+		if err != nil {
+			return NULL, err
+		}
+		f, err := lookupFunction(e.Name, ts)
+		if err != nil {
+			return NULL, err
+		}
+		return f.Returns, nil
+	case *AliasExpr:
+		return c.check(e.Expr)
+	case nil:
+		return NULL, errors.New("invalid expression: missing operand")
+	default:
+		return NULL, fmt.Errorf("invalid expression %T", expr)
+	}
+}
+
+// inferAggregate reads the type of an aggregate already computed by an aggregate operator,
+// or infers it from its argument.
+func (c checker) inferAggregate(e *AggregateExpr) (DataType, error) {
+	if i, err := aggregateColumn(c.cols, e); err == nil {
+		return c.cols[i].Type, nil
+	}
+	if e.Arg == nil {
+		return aggregateType(e.Func, NULL), nil
+	}
+	t, err := c.check(e.Arg)
+	if err != nil {
+		return NULL, err
+	}
+	if (e.Func == SumAggregate || e.Func == AvgAggregate) && t != NULL && numericType(t, t) == NULL {
+		return NULL, fmt.Errorf("%s expects a numeric argument, got %s", e.Func, t)
+	}
+	return aggregateType(e.Func, t), nil
+}
+
+func (c checker) inferBinary(e *BinaryExpr) (DataType, error) {
+	ts, err := c.checkAll(e.LHS, e.RHS)
+	if err != nil {
+		return NULL, err
+	}
+	l, r := ts[0], ts[1]
+
+	switch {
+	case e.Op == AND || e.Op == OR:
+		if err := expectBoolean(e.Op, l); err != nil {
+			return NULL, err
+		}
+		return BOOLEAN, expectBoolean(e.Op, r)
+	case e.Op.IsComparisonOperator():
+		_, err := comparisonType(l, r)
+		return BOOLEAN, err
+	case e.Op == CONCAT:
+		if (l != TEXT && l != NULL) || (r != TEXT && r != NULL) {
+			return NULL, fmt.Errorf("cannot concatenate %s and %s", l, r)
+		}
+		return TEXT, nil
+	case e.Op.IsArithmeticOperator():
+		if (l != NULL && numericType(l, l) == NULL) || (r != NULL && numericType(r, r) == NULL) {
+			return NULL, fmt.Errorf("cannot apply %s to %s and %s", e.Op.Symbol(), l, r)
+		}
+		return numericType(l, r), nil
+	default:
+		return NULL, fmt.Errorf("unsupported operator %s", e.Op)
+	}
+}
+
+func (c checker) inferCase(e *CaseExpr) (DataType, error) {
+	operand, err := c.checkAll(e.Operand)
+	if err != nil {
+		return NULL, err
+	}
+	var results []DataType
+	for _, w := range e.Whens {
+		ts, err := c.checkAll(w.Cond, w.Result)
+		if err != nil {
+			return NULL, err
+		}
+		if e.Operand != nil {
+			if _, err := comparisonType(operand[0], ts[0]); err != nil {
+				return NULL, err
+			}
+		} else if err := expectBoolean(WHEN, ts[0]); err != nil {
+			return NULL, err
+		}
+		results = append(results, ts[1])
+	}
+	if e.Else != nil {
+		t, err := c.check(e.Else)
+		if err != nil {
+			return NULL, err
+		}
+		results = append(results, t)
+	}
+
+	t, err := commonType(results...)
+	if err != nil {
+		return NULL, fmt.Errorf("CASE %w", err)
+	}
+	return t, nil
+}
+
+// expectBoolean ensures the operand of a logical operator is a BOOLEAN.
+func expectBoolean(op Token, t DataType) error {
+	if t != BOOLEAN && t != NULL {
+		return fmt.Errorf("argument of %s must be BOOLEAN, not %s", op, t)
+	}
+	return nil
+}